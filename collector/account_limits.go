@@ -0,0 +1,149 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape the connection limits of `mysql.user` and the connections of `performance_schema.users`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Subsystem.
+	account = "account"
+	// ER_TABLEACCESS_DENIED_ERROR, returned without the SELECT privilege on mysql.user.
+	tableAccessDeniedError = 1142
+)
+
+// A max_user_connections of 0 means the global max_user_connections
+// applies, which is unlimited when 0 as well. The limit is per account, the
+// accounts of a user may have different ones.
+const accountLimitsQuery = `
+	SELECT
+	    user,
+	    host,
+	    max_user_connections,
+	    @@global.max_user_connections AS global_max_user_connections
+	  FROM mysql.user
+	`
+
+// Background threads have no user.
+const accountConnectionsQuery = `
+	SELECT
+	    USER,
+	    CURRENT_CONNECTIONS
+	  FROM performance_schema.users
+	  WHERE USER IS NOT NULL
+	`
+
+// Metric descriptors.
+var (
+	accountMaxConnectionsDesc = newNamespacedDesc(
+		account, "max_connections",
+		"The maximum number of simultaneous connections of the account, its max_user_connections or the global one. Absent when unlimited.",
+		[]string{"user", "host"}, nil,
+	)
+	accountCurrentConnectionsDesc = newNamespacedDesc(
+		account, "current_connections",
+		"The number of current connections of the user.",
+		[]string{"user"}, nil,
+	)
+)
+
+// ScrapeAccountLimits collects from `mysql.user` and `performance_schema.users`.
+type ScrapeAccountLimits struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeAccountLimits) Name() string {
+	return "account_limits"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeAccountLimits) Help() string {
+	return "Collect the connection limits of the accounts from mysql.user along with the current connections of the users from performance_schema.users"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeAccountLimits) Version() float64 {
+	return 5.6
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeAccountLimits) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	if err := scrapeAccountMaxConnections(ctx, db, ch); err != nil {
+		var mysqlErr *gomysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr.Number != tableAccessDeniedError {
+			return err
+		}
+		level.Debug(logger).Log("msg", "Skipping the account limits, reading mysql.user is denied", "err", err)
+	}
+
+	connectionsRows, err := db.QueryContext(ctx, accountConnectionsQuery)
+	if err != nil {
+		return err
+	}
+	defer connectionsRows.Close()
+
+	var (
+		user        string
+		connections float64
+	)
+	for connectionsRows.Next() {
+		if err := connectionsRows.Scan(&user, &connections); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			accountCurrentConnectionsDesc, prometheus.GaugeValue, connections, user,
+		)
+	}
+	return connectionsRows.Err()
+}
+
+func scrapeAccountMaxConnections(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric) error {
+	limitsRows, err := db.QueryContext(ctx, accountLimitsQuery)
+	if err != nil {
+		return err
+	}
+	defer limitsRows.Close()
+
+	var (
+		user, host                           string
+		maxConnections, globalMaxConnections float64
+	)
+	for limitsRows.Next() {
+		if err := limitsRows.Scan(&user, &host, &maxConnections, &globalMaxConnections); err != nil {
+			return err
+		}
+		if maxConnections == 0 {
+			maxConnections = globalMaxConnections
+		}
+		if maxConnections == 0 {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			accountMaxConnectionsDesc, prometheus.GaugeValue, maxConnections, user, host,
+		)
+	}
+	return limitsRows.Err()
+}
+
+// check interface
+var _ Scraper = ScrapeAccountLimits{}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapeAccountLimits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// Only the app account from 10.% has its own limit, the others fall back to the global one.
+	limitsColumns := []string{"user", "host", "max_user_connections", "global_max_user_connections"}
+	limitsRows := sqlmock.NewRows(limitsColumns).
+		AddRow("app", "10.%", "200", "50").
+		AddRow("app", "localhost", "0", "50").
+		AddRow("report", "%", "0", "50")
+	mock.ExpectQuery(sanitizeQuery(accountLimitsQuery)).WillReturnRows(limitsRows)
+	connectionsRows := sqlmock.NewRows([]string{"USER", "CURRENT_CONNECTIONS"}).
+		AddRow("app", "180").
+		AddRow("report", "3")
+	mock.ExpectQuery(sanitizeQuery(accountConnectionsQuery)).WillReturnRows(connectionsRows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeAccountLimits{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_account_max_connections", MetricResult{labels: labelMap{"user": "app", "host": "10.%"}, value: 200, metricType: dto.MetricType_GAUGE}},
+		{"mysql_account_max_connections", MetricResult{labels: labelMap{"user": "app", "host": "localhost"}, value: 50, metricType: dto.MetricType_GAUGE}},
+		{"mysql_account_max_connections", MetricResult{labels: labelMap{"user": "report", "host": "%"}, value: 50, metricType: dto.MetricType_GAUGE}},
+		{"mysql_account_current_connections", MetricResult{labels: labelMap{"user": "app"}, value: 180, metricType: dto.MetricType_GAUGE}},
+		{"mysql_account_current_connections", MetricResult{labels: labelMap{"user": "report"}, value: 3, metricType: dto.MetricType_GAUGE}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeAccountLimitsDenied(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(sanitizeQuery(accountLimitsQuery)).WillReturnError(&gomysql.MySQLError{
+		Number:  tableAccessDeniedError,
+		Message: "SELECT command denied to user 'exporter'@'localhost' for table 'user'",
+	})
+	connectionsRows := sqlmock.NewRows([]string{"USER", "CURRENT_CONNECTIONS"}).AddRow("app", "180")
+	mock.ExpectQuery(sanitizeQuery(accountConnectionsQuery)).WillReturnRows(connectionsRows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeAccountLimits{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	convey.Convey("Metrics comparison", t, func() {
+		m := <-ch
+		convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"mysql_account_current_connections"`)
+		convey.So(readMetric(m), convey.ShouldResemble, MetricResult{labels: labelMap{"user": "app"}, value: 180, metricType: dto.MetricType_GAUGE})
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -36,18 +36,18 @@ const (
 
 // Metric descriptors.
 var (
-	binlogSizeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, binlog, "size_bytes"),
+	binlogSizeDesc = newNamespacedDesc(
+		binlog, "size_bytes",
 		"Combined size of all registered binlog files.",
 		[]string{}, nil,
 	)
-	binlogFilesDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, binlog, "files"),
+	binlogFilesDesc = newNamespacedDesc(
+		binlog, "files",
 		"Number of registered binlog files.",
 		[]string{}, nil,
 	)
-	binlogFileNumberDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, binlog, "file_number"),
+	binlogFileNumberDesc = newNamespacedDesc(
+		binlog, "file_number",
 		"The last binlog file number.",
 		[]string{}, nil,
 	)
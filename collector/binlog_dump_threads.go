@@ -0,0 +1,72 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape binlog dump threads from `information_schema.processlist`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const binlogDumpThreadsQuery = `
+	SELECT COUNT(*)
+	  FROM information_schema.processlist
+	  WHERE command IN ('Binlog Dump', 'Binlog Dump GTID')
+	`
+
+// Metric descriptors.
+var (
+	binlogDumpThreadsDesc = newNamespacedDesc(
+		binlog, "dump_threads",
+		"Number of binlog dump threads, i.e. replicas currently streaming from this server.",
+		[]string{}, nil,
+	)
+)
+
+// ScrapeBinlogDumpThreads counts binlog dump threads from `information_schema.processlist`.
+type ScrapeBinlogDumpThreads struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeBinlogDumpThreads) Name() string {
+	return "binlog_dump_threads"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeBinlogDumpThreads) Help() string {
+	return "Collect the number of binlog dump threads serving replicas"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeBinlogDumpThreads) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeBinlogDumpThreads) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var threads uint64
+	if err := db.QueryRowContext(ctx, binlogDumpThreadsQuery).Scan(&threads); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		binlogDumpThreadsDesc, prometheus.GaugeValue, float64(threads),
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeBinlogDumpThreads{}
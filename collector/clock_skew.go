@@ -0,0 +1,73 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape the server clock.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const clockSkewQuery = `SELECT UNIX_TIMESTAMP(NOW(6))`
+
+// Metric descriptors.
+var (
+	clockSkewDesc = newNamespacedDesc(
+		"", "clock_skew_seconds",
+		"Difference between the server clock and the exporter clock in seconds, positive when the server is ahead.",
+		nil, nil,
+	)
+)
+
+// ScrapeClockSkew compares the server clock with the exporter clock.
+type ScrapeClockSkew struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeClockSkew) Name() string {
+	return "clock_skew"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeClockSkew) Help() string {
+	return "Collect the difference between the server clock and the exporter clock"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeClockSkew) Version() float64 {
+	return 5.6
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeClockSkew) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var serverTime float64
+	start := time.Now()
+	if err := db.QueryRowContext(ctx, clockSkewQuery).Scan(&serverTime); err != nil {
+		return err
+	}
+	// The server read its clock somewhere during the round trip, assume halfway.
+	localTime := start.Add(time.Since(start) / 2)
+
+	ch <- prometheus.MustNewConstMetric(
+		clockSkewDesc, prometheus.GaugeValue, serverTime-float64(localTime.UnixNano())/1e9,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeClockSkew{}
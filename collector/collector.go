@@ -20,17 +20,22 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Namespace is the default prefix of the metric names built by the collectors.
+const Namespace = "mysql"
+
 const (
-	// Exporter namespace.
-	namespace = "mysql"
 	// Math constant for picoseconds to seconds.
 	picoSeconds = 1e12
 )
 
+// Exporter namespace, changed by SetNamespace.
+var namespace = Namespace
+
 var logRE = regexp.MustCompile(`.+\.(\d+)$`)
 
 func newDesc(subsystem, name, help string) *prometheus.Desc {
@@ -40,6 +45,33 @@ func newDesc(subsystem, name, help string) *prometheus.Desc {
 	)
 }
 
+// namespacedDescs keeps how the package level descriptors were built, for SetNamespace.
+var namespacedDescs = map[*prometheus.Desc]namespacedDescArgs{}
+
+type namespacedDescArgs struct {
+	subsystem, name, help string
+	variableLabels        []string
+	constLabels           prometheus.Labels
+}
+
+// newNamespacedDesc returns a descriptor which SetNamespace renames. It is
+// meant for package level descriptors, descriptors built while scraping
+// use the namespace as is.
+func newNamespacedDesc(subsystem, name, help string, variableLabels []string, constLabels prometheus.Labels) *prometheus.Desc {
+	desc := prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, variableLabels, constLabels)
+	namespacedDescs[desc] = namespacedDescArgs{subsystem, name, help, variableLabels, constLabels}
+	return desc
+}
+
+// SetNamespace changes the prefix of the metric names built by the collectors
+// from Namespace to ns. It must be called before any collector is used.
+func SetNamespace(ns string) {
+	namespace = ns
+	for desc, args := range namespacedDescs {
+		*desc = *prometheus.NewDesc(prometheus.BuildFQName(ns, args.subsystem, args.name), args.help, args.variableLabels, args.constLabels)
+	}
+}
+
 func parseStatus(data sql.RawBytes) (float64, bool) {
 	dataString := strings.ToLower(string(data))
 	switch dataString {
@@ -79,3 +111,17 @@ func parsePrivilege(data sql.RawBytes) (float64, bool) {
 	}
 	return -1, false
 }
+
+// labelValue makes a value read from the server usable as a label value: it
+// replaces invalid UTF-8, which client_golang rejects, and truncates it to at
+// most maxLength bytes on a rune boundary. A maxLength of 0 keeps the length.
+func labelValue(s string, maxLength int) string {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	if maxLength <= 0 || len(s) <= maxLength {
+		return s
+	}
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+	return s[:maxLength]
+}
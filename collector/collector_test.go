@@ -15,9 +15,11 @@ package collector
 
 import (
 	"strings"
+	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
 )
 
 type labelMap map[string]string
@@ -52,5 +54,28 @@ func sanitizeQuery(q string) string {
 	q = strings.Replace(q, "(", "\\(", -1)
 	q = strings.Replace(q, ")", "\\)", -1)
 	q = strings.Replace(q, "*", "\\*", -1)
+	q = strings.Replace(q, "?", "\\?", -1)
+	q = strings.Replace(q, "$", "\\$", -1)
 	return q
 }
+
+func TestLabelValue(t *testing.T) {
+	convey.Convey("Label values", t, func() {
+		convey.So(labelValue("short", 10), convey.ShouldEqual, "short")
+		convey.So(labelValue("naïve", 3), convey.ShouldEqual, "na")
+		convey.So(labelValue("naïve", 4), convey.ShouldEqual, "naï")
+		convey.So(labelValue("bad\xff", 0), convey.ShouldEqual, "bad\uFFFD")
+	})
+}
+
+func TestSetNamespace(t *testing.T) {
+	defer SetNamespace(Namespace)
+
+	SetNamespace("fork")
+	convey.Convey("Metric names use the namespace", t, func() {
+		convey.So(globalCommandsDesc.String(), convey.ShouldContainSubstring, `fqName: "fork_global_status_commands_total"`)
+		convey.So(globalCommandsDesc.String(), convey.ShouldContainSubstring, `variableLabels: [command]`)
+		convey.So(newDesc(globalStatus, "uptime", "Uptime.").String(), convey.ShouldContainSubstring, `fqName: "fork_global_status_uptime"`)
+		convey.So(NewMetrics().TotalScrapes.Desc().String(), convey.ShouldContainSubstring, `fqName: "fork_exporter_scrapes_total"`)
+	})
+}
@@ -0,0 +1,381 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Tunable flags.
+var (
+	maxExecutionTime = kingpin.Flag(
+		"mysqld.max-execution-time",
+		"Maximum execution time (in milliseconds) of the exporter's queries, enforced server-side. 0 disables the limit.",
+	).Default("0").Int()
+	initSQL = kingpin.Flag(
+		"mysqld.init-sql",
+		"SET statement of session variables run on each new connection, in order. Can be repeated.",
+	).Action(validateInitSQL).Strings()
+	annotateQueries = kingpin.Flag(
+		"collect.annotate-queries",
+		"Prefix the queries of each collector with a /* mysqld_exporter:<collector> */ comment, to identify them in the processlist and slow log.",
+	).Default("false").Bool()
+	driverTimeout = kingpin.Flag(
+		"mysqld.timeout",
+		"Timeout for establishing connections, the timeout DSN parameter. 0 uses the driver default.",
+	).Default("0s").Action(validateDriverDuration("mysqld.timeout")).Duration()
+	driverReadTimeout = kingpin.Flag(
+		"mysqld.read-timeout",
+		"I/O read timeout, the readTimeout DSN parameter. 0 disables it.",
+	).Default("0s").Action(validateDriverDuration("mysqld.read-timeout")).Duration()
+	driverWriteTimeout = kingpin.Flag(
+		"mysqld.write-timeout",
+		"I/O write timeout, the writeTimeout DSN parameter. 0 disables it.",
+	).Default("0s").Action(validateDriverDuration("mysqld.write-timeout")).Duration()
+	driverCharset = kingpin.Flag(
+		"mysqld.charset",
+		"Connection character set, the charset DSN parameter.",
+	).Default("").String()
+	driverInterpolateParams = kingpin.Flag(
+		"mysqld.interpolate-params",
+		"Interpolate the query placeholders client-side rather than preparing statements, the interpolateParams DSN parameter.",
+	).Default("false").Bool()
+	infoSchemaMaxExecutionTime = kingpin.Flag(
+		"collect.info_schema.max-execution-time",
+		"Maximum execution time (in milliseconds) of the SELECT queries of the info_schema collectors, enforced server-side on top of --mysqld.max-execution-time. 0 disables the limit.",
+	).Default("0").Int()
+)
+
+// sessionConnector wraps the MySQL driver connector to set up every new
+// connection of the pool before it is handed out to the scrapers.
+type sessionConnector struct {
+	driver.Connector
+}
+
+// openDB opens a database handle for the DSN whose connections are initialized by initSession.
+func openDB(dsn string) (*sql.DB, error) {
+	cfg, err := gomysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := gomysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(&sessionConnector{Connector: connector}), nil
+}
+
+// Connect implements driver.Connector.
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := initSession(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	mc, ok := conn.(mysqlConn)
+	if !ok {
+		return conn, nil
+	}
+	if *annotateQueries {
+		mc = annotatedConn{mc}
+	}
+	if *infoSchemaMaxExecutionTime > 0 {
+		mariaDB, err := isMariaDB(ctx, conn)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		// Applied before the annotation, so the hint directly follows SELECT.
+		mc = limitedConn{mysqlConn: mc, mariaDB: mariaDB}
+	}
+	return mc, nil
+}
+
+// mysqlConn lists the interfaces of the MySQL driver connections, which
+// database/sql uses when they are implemented.
+type mysqlConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.NamedValueChecker
+	driver.SessionResetter
+	driver.Validator
+}
+
+// annotatedConn prefixes the queries with the annotation of their context.
+type annotatedConn struct {
+	mysqlConn
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c annotatedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.mysqlConn.QueryContext(ctx, annotateQuery(ctx, query), args)
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c annotatedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.mysqlConn.ExecContext(ctx, annotateQuery(ctx, query), args)
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c annotatedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.mysqlConn.PrepareContext(ctx, annotateQuery(ctx, query))
+}
+
+// limitedConn limits the execution time of the SELECT queries of the
+// info_schema collectors, which can scan a lot of rows.
+type limitedConn struct {
+	mysqlConn
+	mariaDB bool
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c limitedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.mysqlConn.QueryContext(ctx, limitQuery(ctx, query, c.mariaDB), args)
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c limitedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.mysqlConn.PrepareContext(ctx, limitQuery(ctx, query, c.mariaDB))
+}
+
+// limitQuery adds --collect.info_schema.max-execution-time to a SELECT query
+// of an info_schema collector. MySQL takes it as an optimizer hint, MariaDB
+// has no such hint but sets max_statement_time for a single statement.
+func limitQuery(ctx context.Context, query string, mariaDB bool) string {
+	collector, ok := ctx.Value(queryAnnotationKey{}).(string)
+	if !ok || !strings.HasPrefix(collector, informationSchema+".") {
+		return query
+	}
+	trimmed := strings.TrimLeft(query, " \t\r\n")
+	if len(trimmed) < len("SELECT") || !strings.EqualFold(trimmed[:len("SELECT")], "SELECT") {
+		return query
+	}
+	if mariaDB {
+		return fmt.Sprintf("SET STATEMENT max_statement_time = %g FOR %s", float64(*infoSchemaMaxExecutionTime)/1000, trimmed)
+	}
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */%s", *infoSchemaMaxExecutionTime, trimmed[len("SELECT"):])
+}
+
+type queryAnnotationKey struct{}
+
+// withQueryAnnotation returns a context whose queries are annotated with the collector name.
+func withQueryAnnotation(ctx context.Context, collector string) context.Context {
+	return context.WithValue(ctx, queryAnnotationKey{}, collector)
+}
+
+func annotateQuery(ctx context.Context, query string) string {
+	collector, ok := ctx.Value(queryAnnotationKey{}).(string)
+	if !ok {
+		return query
+	}
+	return "/* mysqld_exporter:" + collector + " */ " + query
+}
+
+// initSession applies the session settings to a new connection.
+func initSession(ctx context.Context, conn driver.Conn) error {
+	if *maxExecutionTime > 0 {
+		mariaDB, err := isMariaDB(ctx, conn)
+		if err != nil {
+			return err
+		}
+		// MariaDB has max_statement_time in seconds instead of max_execution_time in milliseconds.
+		stmt := fmt.Sprintf("SET SESSION max_execution_time = %d", *maxExecutionTime)
+		if mariaDB {
+			stmt = fmt.Sprintf("SET SESSION max_statement_time = %g", float64(*maxExecutionTime)/1000)
+		}
+		if err := execConn(ctx, conn, stmt); err != nil {
+			return err
+		}
+	}
+	for _, stmt := range *initSQL {
+		if err := execConn(ctx, conn, stmt); err != nil {
+			return fmt.Errorf("init SQL %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// initSQLRejectedWords are the SET forms that reach beyond the session:
+// server wide or persisted variables, passwords and roles.
+var initSQLRejectedWords = map[string]bool{
+	"global":       true,
+	"persist":      true,
+	"persist_only": true,
+	"password":     true,
+	"role":         true,
+}
+
+// initSQLRejectedPrefixes are the variable references with the same effect.
+var initSQLRejectedPrefixes = []string{"@@global.", "@@persist.", "@@persist_only."}
+
+// checkInitSQL only accepts SET statements of session variables, which can't
+// change any data, so the init SQL stays a session setup rather than a way
+// to run arbitrary queries or reconfigure the server.
+func checkInitSQL(stmt string) error {
+	fields := strings.Fields(stmt)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "SET") {
+		return fmt.Errorf("init SQL %q is not a SET statement", stmt)
+	}
+	if strings.Contains(stmt, ";") {
+		return fmt.Errorf("init SQL %q must be a single statement", stmt)
+	}
+	words := strings.FieldsFunc(strings.ToLower(stmt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_@.", r)
+	})
+	for _, word := range words {
+		if initSQLRejectedWords[word] {
+			return fmt.Errorf("init SQL %q may only set session variables", stmt)
+		}
+		for _, prefix := range initSQLRejectedPrefixes {
+			if strings.HasPrefix(word, prefix) {
+				return fmt.Errorf("init SQL %q may only set session variables", stmt)
+			}
+		}
+	}
+	return nil
+}
+
+// validateInitSQL rejects invalid init SQL at startup.
+func validateInitSQL(ctx *kingpin.ParseContext) error {
+	for _, element := range ctx.Elements {
+		flag, ok := element.Clause.(*kingpin.FlagClause)
+		if !ok || flag.Model().Name != "mysqld.init-sql" || element.Value == nil {
+			continue
+		}
+		if err := checkInitSQL(*element.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// driverParams returns the DSN parameters set by the --mysqld.* driver flags,
+// except those already in the DSN which take precedence.
+func driverParams(dsn string) []string {
+	present := dsnParamNames(dsn)
+	var params []string
+	add := func(name, value string) {
+		if !present[name] {
+			params = append(params, name+"="+url.QueryEscape(value))
+		}
+	}
+	if *driverTimeout > 0 {
+		add("timeout", driverTimeout.String())
+	}
+	if *driverReadTimeout > 0 {
+		add("readTimeout", driverReadTimeout.String())
+	}
+	if *driverWriteTimeout > 0 {
+		add("writeTimeout", driverWriteTimeout.String())
+	}
+	if *driverCharset != "" {
+		add("charset", *driverCharset)
+	}
+	if *driverInterpolateParams {
+		add("interpolateParams", "true")
+	}
+	return params
+}
+
+// dsnParamNames returns the names of the parameters of the DSN. Like the
+// driver, it looks for them after the last slash, as the password may
+// contain one.
+func dsnParamNames(dsn string) map[string]bool {
+	names := map[string]bool{}
+	rest := dsn[strings.LastIndex(dsn, "/")+1:]
+	i := strings.Index(rest, "?")
+	if i < 0 {
+		return names
+	}
+	for _, param := range strings.Split(rest[i+1:], "&") {
+		names[strings.SplitN(param, "=", 2)[0]] = true
+	}
+	return names
+}
+
+// validateDriverDuration rejects a negative timeout flag at startup.
+func validateDriverDuration(name string) kingpin.Action {
+	return func(ctx *kingpin.ParseContext) error {
+		for _, element := range ctx.Elements {
+			flag, ok := element.Clause.(*kingpin.FlagClause)
+			if !ok || flag.Model().Name != name || element.Value == nil {
+				continue
+			}
+			d, err := time.ParseDuration(*element.Value)
+			if err != nil {
+				return fmt.Errorf("invalid --%s %q: %w", name, *element.Value, err)
+			}
+			if d < 0 {
+				return fmt.Errorf("invalid --%s %q, must not be negative", name, *element.Value)
+			}
+		}
+		return nil
+	}
+}
+
+func execConn(ctx context.Context, conn driver.Conn, query string) error {
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		return fmt.Errorf("connection does not support executing %q", query)
+	}
+	_, err := execer.ExecContext(ctx, query, nil)
+	return err
+}
+
+// isMariaDB tells whether the connection is to a MariaDB server.
+func isMariaDB(ctx context.Context, conn driver.Conn) (bool, error) {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return false, fmt.Errorf("connection does not support querying %q", versionQuery)
+	}
+	rows, err := queryer.QueryContext(ctx, versionQuery, nil)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, len(rows.Columns()))
+	if err := rows.Next(values); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, err
+	}
+	var version string
+	switch v := values[0].(type) {
+	case []byte:
+		version = string(v)
+	case string:
+		version = v
+	}
+	return strings.Contains(strings.ToLower(version), "mariadb"), nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestInitSession(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--mysqld.max-execution-time=1500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	convey.Convey("Session initialization", t, func() {
+		for _, tc := range []struct {
+			version, stmt string
+		}{
+			{"8.0.26", "SET SESSION max_execution_time = 1500"},
+			{"10.5.12-MariaDB-log", "SET SESSION max_statement_time = 1.5"},
+		} {
+			db, mock, err := sqlmock.NewWithDSN("init_session_" + tc.version)
+			convey.So(err, convey.ShouldBeNil)
+
+			mock.ExpectQuery(sanitizeQuery(versionQuery)).WillReturnRows(sqlmock.NewRows([]string{"@@version"}).AddRow(tc.version))
+			mock.ExpectExec(sanitizeQuery(tc.stmt)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			conn, err := db.Driver().Open("init_session_" + tc.version)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(initSession(context.Background(), conn), convey.ShouldBeNil)
+			convey.So(mock.ExpectationsWereMet(), convey.ShouldBeNil)
+			db.Close()
+		}
+	})
+}
+
+func TestInitSessionInitSQL(t *testing.T) {
+	// Repeatable flags accumulate across parses, start from a clean list.
+	*initSQL = nil
+	defer func() { *initSQL = nil }()
+	_, err := kingpin.CommandLine.Parse([]string{
+		"--mysqld.init-sql", "SET NAMES utf8mb4",
+		"--mysqld.init-sql", "SET RESOURCE GROUP monitoring",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.NewWithDSN("init_session_init_sql")
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(sanitizeQuery("SET NAMES utf8mb4")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(sanitizeQuery("SET RESOURCE GROUP monitoring")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	convey.Convey("Init SQL runs in order", t, func() {
+		conn, err := db.Driver().Open("init_session_init_sql")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(initSession(context.Background(), conn), convey.ShouldBeNil)
+		convey.So(mock.ExpectationsWereMet(), convey.ShouldBeNil)
+	})
+}
+
+func TestCheckInitSQL(t *testing.T) {
+	convey.Convey("Init SQL validation", t, func() {
+		convey.So(checkInitSQL("SET SESSION sql_mode = ''"), convey.ShouldBeNil)
+		convey.So(checkInitSQL("set names utf8mb4"), convey.ShouldBeNil)
+		convey.So(checkInitSQL("DELETE FROM mysql.user"), convey.ShouldNotBeNil)
+		convey.So(checkInitSQL("SET NAMES utf8mb4; DROP TABLE t"), convey.ShouldNotBeNil)
+		convey.So(checkInitSQL(""), convey.ShouldNotBeNil)
+		convey.So(checkInitSQL("SET @@session.max_execution_time = 1000, @@SESSION.sql_mode = ''"), convey.ShouldBeNil)
+		for _, stmt := range []string{
+			"SET GLOBAL read_only = ON",
+			"SET SESSION sql_mode = '', global read_only = ON",
+			"SET PERSIST max_connections = 1",
+			"SET PERSIST_ONLY max_connections = 1",
+			"SET @@global.read_only = ON",
+			"SET @@GLOBAL.read_only = ON",
+			"SET @@persist.max_connections = 1",
+			"SET @@persist_only.max_connections = 1",
+			"SET PASSWORD = 'secret'",
+			"SET PASSWORD FOR root@localhost = 'secret'",
+			"SET ROLE ALL",
+			"SET DEFAULT ROLE admin TO exporter",
+		} {
+			convey.So(checkInitSQL(stmt), convey.ShouldNotBeNil)
+		}
+
+		_, err := kingpin.CommandLine.Parse([]string{"--mysqld.init-sql", "TRUNCATE t"})
+		convey.So(err, convey.ShouldNotBeNil)
+		kingpin.CommandLine.Parse([]string{})
+		*initSQL = nil
+	})
+}
+
+// recordingConn records the queries it is sent.
+type recordingConn struct {
+	mysqlConn
+	queries []string
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.queries = append(c.queries, query)
+	return nil, nil
+}
+
+func TestAnnotatedConn(t *testing.T) {
+	convey.Convey("Query annotation", t, func() {
+		recorder := &recordingConn{}
+		conn := annotatedConn{recorder}
+
+		conn.QueryContext(withQueryAnnotation(context.Background(), "global_status"), globalStatusQuery, nil)
+		conn.QueryContext(context.Background(), versionQuery, nil)
+		convey.So(recorder.queries, convey.ShouldResemble, []string{
+			"/* mysqld_exporter:global_status */ SHOW GLOBAL STATUS",
+			versionQuery,
+		})
+	})
+}
+
+func TestLimitedConn(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.info_schema.max-execution-time=1500"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	ctx := withQueryAnnotation(context.Background(), "info_schema.tables")
+	convey.Convey("MySQL optimizer hint", t, func() {
+		recorder := &recordingConn{}
+		conn := limitedConn{mysqlConn: recorder}
+
+		conn.QueryContext(ctx, "\n\tSELECT TABLE_NAME FROM information_schema.tables", nil)
+		conn.QueryContext(ctx, "SHOW ENGINE INNODB STATUS", nil)
+		conn.QueryContext(withQueryAnnotation(context.Background(), "global_status"), "SELECT 1", nil)
+		convey.So(recorder.queries, convey.ShouldResemble, []string{
+			"SELECT /*+ MAX_EXECUTION_TIME(1500) */ TABLE_NAME FROM information_schema.tables",
+			"SHOW ENGINE INNODB STATUS",
+			"SELECT 1",
+		})
+	})
+	convey.Convey("MariaDB statement time", t, func() {
+		recorder := &recordingConn{}
+		conn := limitedConn{mysqlConn: recorder, mariaDB: true}
+
+		conn.QueryContext(ctx, "SELECT TABLE_NAME FROM information_schema.tables", nil)
+		convey.So(recorder.queries, convey.ShouldResemble, []string{
+			"SET STATEMENT max_statement_time = 1.5 FOR SELECT TABLE_NAME FROM information_schema.tables",
+		})
+	})
+}
+
+func TestDriverParams(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{
+		"--mysqld.timeout=5s",
+		"--mysqld.read-timeout=30s",
+		"--mysqld.charset=utf8mb4",
+		"--mysqld.interpolate-params",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	convey.Convey("Driver params", t, func() {
+		convey.So(driverParams("user:pass@tcp(localhost:3306)/"), convey.ShouldResemble, []string{
+			"timeout=5s", "readTimeout=30s", "charset=utf8mb4", "interpolateParams=true",
+		})
+		// Explicit DSN parameters take precedence, the password may contain a slash or a question mark.
+		convey.So(driverParams("user:p/a?ss@tcp(localhost:3306)/?readTimeout=1m&charset=latin1"), convey.ShouldResemble, []string{
+			"timeout=5s", "interpolateParams=true",
+		})
+	})
+	convey.Convey("Invalid durations", t, func() {
+		_, err := kingpin.CommandLine.Parse([]string{"--mysqld.read-timeout=-1s"})
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = kingpin.CommandLine.Parse([]string{"--mysqld.write-timeout=soon"})
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
@@ -29,8 +29,72 @@ import (
 const (
 	// Subsystem.
 	innodb = "engine_innodb"
+	// Subsystem of the structured metrics parsed from the status sections.
+	innodbStatus = "innodb"
 	// Query.
 	engineInnodbStatusQuery = `SHOW ENGINE INNODB STATUS`
+	// The redo log capacity is innodb_redo_log_capacity as of 8.0.30, the
+	// product of the size and number of log files before.
+	innodbRedoLogCapacityQuery = `SHOW GLOBAL VARIABLES WHERE Variable_name IN ('innodb_redo_log_capacity', 'innodb_log_file_size', 'innodb_log_files_in_group')`
+)
+
+// Metric descriptors.
+var (
+	innodbQueriesInsideDesc = newNamespacedDesc(
+		innodbStatus, "queries_inside",
+		"Number of queries currently executing inside InnoDB, from the ROW OPERATIONS section.",
+		nil, nil,
+	)
+	innodbQueriesQueuedDesc = newNamespacedDesc(
+		innodbStatus, "queries_queued",
+		"Number of queries waiting to enter InnoDB, from the ROW OPERATIONS section.",
+		nil, nil,
+	)
+	innodbMainThreadInfoDesc = newNamespacedDesc(
+		innodbStatus, "main_thread_info",
+		"The state of the InnoDB main thread, from the ROW OPERATIONS section.",
+		[]string{"state"}, nil,
+	)
+	innodbIbufMergesDesc = newNamespacedDesc(
+		innodbStatus, "ibuf_merges_total",
+		"Total number of change buffer operations merged into secondary indexes, from the INSERT BUFFER AND ADAPTIVE HASH INDEX section.",
+		[]string{"operation"}, nil,
+	)
+	innodbIbufSizeDesc = newNamespacedDesc(
+		innodbStatus, "ibuf_size",
+		"Size of the change buffer in pages, from the INSERT BUFFER AND ADAPTIVE HASH INDEX section.",
+		nil, nil,
+	)
+	innodbAdaptiveHashSearchesDesc = newNamespacedDesc(
+		innodbStatus, "adaptive_hash_searches_per_second",
+		"Searches per second using the adaptive hash index or not, averaged since the previous status output, from the INSERT BUFFER AND ADAPTIVE HASH INDEX section.",
+		[]string{"type"}, nil,
+	)
+	innodbSemaphoreSpinWaitsDesc = newNamespacedDesc(
+		innodbStatus, "semaphore_spin_waits_total",
+		"Total number of spin waits on InnoDB mutexes and rw-locks by lock type, from the SEMAPHORES section.",
+		[]string{"type"}, nil,
+	)
+	innodbSemaphoreSpinRoundsDesc = newNamespacedDesc(
+		innodbStatus, "semaphore_spin_rounds_total",
+		"Total number of spin loop rounds on InnoDB mutexes and rw-locks by lock type, from the SEMAPHORES section.",
+		[]string{"type"}, nil,
+	)
+	innodbCheckpointAgeDesc = newNamespacedDesc(
+		innodbStatus, "checkpoint_age_bytes",
+		"Redo log written since the last checkpoint in bytes, from the LOG section.",
+		nil, nil,
+	)
+	innodbCheckpointAgeRatioDesc = newNamespacedDesc(
+		innodbStatus, "checkpoint_age_ratio",
+		"Checkpoint age relative to the redo log capacity. InnoDB flushes aggressively, stalling writes, as it nears the async and sync flush points at about 0.75 and 0.9.",
+		nil, nil,
+	)
+	innodbSemaphoreWaitsDesc = newNamespacedDesc(
+		innodbStatus, "semaphore_waits_total",
+		"Total number of OS waits on InnoDB mutexes and rw-locks by lock type after spinning failed, from the SEMAPHORES section. Sustained OS waits indicate contention.",
+		[]string{"type"}, nil,
+	)
 )
 
 // ScrapeEngineInnodbStatus scrapes from `SHOW ENGINE INNODB STATUS`.
@@ -69,11 +133,65 @@ func (ScrapeEngineInnodbStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<
 
 	// 0 queries inside InnoDB, 0 queries in queue
 	// 0 read views open inside InnoDB
-	rQueries, _ := regexp.Compile(`(\d+) queries inside InnoDB, (\d+) queries in queue`)
+	// Process ID=1, Main thread ID=140656308950784, state: sleeping
+	// Main thread process no. 3032, id 140218046289664, state: waiting for server activity
+	rQueries, _ := regexp.Compile(`(\d+) quer(?:y|ies) inside InnoDB, (\d+) quer(?:y|ies) in queue`)
 	rViews, _ := regexp.Compile(`(\d+) read views open inside InnoDB`)
+	rMainThread, _ := regexp.Compile(`Main thread .*state: (.+)$`)
+	// Ibuf: size 1, free list len 0, seg size 2, 0 merges
+	// merged operations:
+	//  insert 0, delete mark 0, delete 0
+	// MySQL 5.1 and older only buffer inserts:
+	// Ibuf: size 1, free list len 0, seg size 2,
+	// 0 inserts, 0 merged recs, 0 merges
+	rIbufSize, _ := regexp.Compile(`^Ibuf: size (\d+),`)
+	rIbufMergedOps, _ := regexp.Compile(`^\s*insert (\d+), delete mark (\d+), delete (\d+)`)
+	rIbufMergedRecs, _ := regexp.Compile(`^(\d+) inserts, (\d+) merged recs, (\d+) merges`)
+	// RW-shared spins 0, rounds 4, OS waits 2
+	// MySQL 5.6 and older also report mutexes:
+	// Mutex spin waits 12, rounds 120, OS waits 3
+	rSemaphore, _ := regexp.Compile(`^(Mutex|RW-shared|RW-excl|RW-sx) spin(?:s| waits) (\d+), rounds (\d+), OS waits (\d+)`)
+	// 0.00 hash searches/s, 0.00 non-hash searches/s
+	rHashSearches, _ := regexp.Compile(`([\d.]+) hash searches/s, ([\d.]+) non-hash searches/s`)
+	// Log sequence number 37771171
+	// Last checkpoint at  37771162
+	rLogSequenceNumber, _ := regexp.Compile(`^Log sequence number\s+(\d+)$`)
+	rLastCheckpoint, _ := regexp.Compile(`^Last checkpoint at\s+(\d+)$`)
+	var logSequenceNumber, lastCheckpoint float64
+	var hasLogSequenceNumber, hasLastCheckpoint bool
 
+	// The merged and discarded operations share the same line format.
+	mergedOperations := false
 	for _, line := range strings.Split(statusCol, "\n") {
-		if data := rQueries.FindStringSubmatch(line); data != nil {
+		if strings.TrimSpace(line) == "merged operations:" {
+			mergedOperations = true
+			continue
+		}
+		if mergedOperations {
+			mergedOperations = false
+			if data := rIbufMergedOps.FindStringSubmatch(line); data != nil {
+				for i, operation := range []string{"insert", "delete_mark", "delete"} {
+					value, _ := strconv.ParseFloat(data[i+1], 64)
+					ch <- prometheus.MustNewConstMetric(innodbIbufMergesDesc, prometheus.CounterValue, value, operation)
+				}
+				continue
+			}
+		}
+		if data := rIbufSize.FindStringSubmatch(line); data != nil {
+			value, _ := strconv.ParseFloat(data[1], 64)
+			ch <- prometheus.MustNewConstMetric(innodbIbufSizeDesc, prometheus.GaugeValue, value)
+		} else if data := rIbufMergedRecs.FindStringSubmatch(line); data != nil {
+			value, _ := strconv.ParseFloat(data[2], 64)
+			ch <- prometheus.MustNewConstMetric(innodbIbufMergesDesc, prometheus.CounterValue, value, "insert")
+		} else if data := rSemaphore.FindStringSubmatch(line); data != nil {
+			lockType := strings.ToLower(strings.Replace(data[1], "-", "_", 1))
+			spinWaits, _ := strconv.ParseFloat(data[2], 64)
+			spinRounds, _ := strconv.ParseFloat(data[3], 64)
+			osWaits, _ := strconv.ParseFloat(data[4], 64)
+			ch <- prometheus.MustNewConstMetric(innodbSemaphoreSpinWaitsDesc, prometheus.CounterValue, spinWaits, lockType)
+			ch <- prometheus.MustNewConstMetric(innodbSemaphoreSpinRoundsDesc, prometheus.CounterValue, spinRounds, lockType)
+			ch <- prometheus.MustNewConstMetric(innodbSemaphoreWaitsDesc, prometheus.CounterValue, osWaits, lockType)
+		} else if data := rQueries.FindStringSubmatch(line); data != nil {
 			value, _ := strconv.ParseFloat(data[1], 64)
 			ch <- prometheus.MustNewConstMetric(
 				newDesc(innodb, "queries_inside_innodb", "Queries inside InnoDB."),
@@ -86,6 +204,9 @@ func (ScrapeEngineInnodbStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<
 				prometheus.GaugeValue,
 				value,
 			)
+			inside, _ := strconv.ParseFloat(data[1], 64)
+			ch <- prometheus.MustNewConstMetric(innodbQueriesInsideDesc, prometheus.GaugeValue, inside)
+			ch <- prometheus.MustNewConstMetric(innodbQueriesQueuedDesc, prometheus.GaugeValue, value)
 		} else if data := rViews.FindStringSubmatch(line); data != nil {
 			value, _ := strconv.ParseFloat(data[1], 64)
 			ch <- prometheus.MustNewConstMetric(
@@ -93,11 +214,69 @@ func (ScrapeEngineInnodbStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<
 				prometheus.GaugeValue,
 				value,
 			)
+		} else if data := rMainThread.FindStringSubmatch(line); data != nil {
+			ch <- prometheus.MustNewConstMetric(
+				innodbMainThreadInfoDesc, prometheus.GaugeValue, 1, strings.TrimSpace(data[1]),
+			)
+		} else if data := rLogSequenceNumber.FindStringSubmatch(line); data != nil {
+			logSequenceNumber, _ = strconv.ParseFloat(data[1], 64)
+			hasLogSequenceNumber = true
+		} else if data := rLastCheckpoint.FindStringSubmatch(line); data != nil {
+			lastCheckpoint, _ = strconv.ParseFloat(data[1], 64)
+			hasLastCheckpoint = true
+		} else if data := rHashSearches.FindStringSubmatch(line); data != nil {
+			// The status output only has rates, the line is absent on some versions when the AHI is disabled.
+			hash, _ := strconv.ParseFloat(data[1], 64)
+			nonHash, _ := strconv.ParseFloat(data[2], 64)
+			ch <- prometheus.MustNewConstMetric(innodbAdaptiveHashSearchesDesc, prometheus.GaugeValue, hash, "hash")
+			ch <- prometheus.MustNewConstMetric(innodbAdaptiveHashSearchesDesc, prometheus.GaugeValue, nonHash, "non_hash")
 		}
 	}
+	if !hasLogSequenceNumber || !hasLastCheckpoint {
+		return nil
+	}
+	checkpointAge := logSequenceNumber - lastCheckpoint
+	ch <- prometheus.MustNewConstMetric(innodbCheckpointAgeDesc, prometheus.GaugeValue, checkpointAge)
+	rows.Close()
 
+	capacity, err := innodbRedoLogCapacity(ctx, db)
+	if err != nil {
+		return err
+	}
+	if capacity > 0 {
+		ch <- prometheus.MustNewConstMetric(innodbCheckpointAgeRatioDesc, prometheus.GaugeValue, checkpointAge/capacity)
+	}
 	return nil
 }
 
+// innodbRedoLogCapacity returns the redo log capacity in bytes, with both
+// the 8.0.30 and the older configuration styles.
+func innodbRedoLogCapacity(ctx context.Context, db *sql.DB) (float64, error) {
+	variablesRows, err := db.QueryContext(ctx, innodbRedoLogCapacityQuery)
+	if err != nil {
+		return 0, err
+	}
+	defer variablesRows.Close()
+
+	var (
+		name  string
+		value float64
+	)
+	variables := map[string]float64{}
+	for variablesRows.Next() {
+		if err := variablesRows.Scan(&name, &value); err != nil {
+			return 0, err
+		}
+		variables[strings.ToLower(name)] = value
+	}
+	if err := variablesRows.Err(); err != nil {
+		return 0, err
+	}
+	if capacity, ok := variables["innodb_redo_log_capacity"]; ok {
+		return capacity, nil
+	}
+	return variables["innodb_log_file_size"] * variables["innodb_log_files_in_group"], nil
+}
+
 // check interface
 var _ Scraper = ScrapeEngineInnodbStatus{}
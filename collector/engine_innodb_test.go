@@ -152,6 +152,10 @@ END OF INNODB MONITOR OUTPUT
 	rows := sqlmock.NewRows(columns).AddRow("InnoDB", "", sample)
 
 	mock.ExpectQuery(sanitizeQuery(engineInnodbStatusQuery)).WillReturnRows(rows)
+	variablesRows := sqlmock.NewRows([]string{"Variable_name", "Value"}).
+		AddRow("innodb_log_file_size", "50").
+		AddRow("innodb_log_files_in_group", "2")
+	mock.ExpectQuery(sanitizeQuery(innodbRedoLogCapacityQuery)).WillReturnRows(variablesRows)
 
 	ch := make(chan prometheus.Metric)
 	go func() {
@@ -162,15 +166,328 @@ END OF INNODB MONITOR OUTPUT
 	}()
 
 	metricsExpected := []MetricResult{
+		{labels: labelMap{"type": "rw_shared"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_shared"}, value: 4, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_shared"}, value: 2, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_excl"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_excl"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_excl"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_sx"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_sx"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "rw_sx"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"operation": "insert"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"operation": "delete_mark"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"operation": "delete"}, value: 0, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"type": "hash"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"type": "non_hash"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 661, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 10, metricType: dto.MetricType_GAUGE},
 		{labels: labelMap{}, value: 661, metricType: dto.MetricType_GAUGE},
 		{labels: labelMap{}, value: 10, metricType: dto.MetricType_GAUGE},
 		{labels: labelMap{}, value: 15, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"state": "sleeping"}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 9, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 0.09, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricsExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeEngineInnodbStatusAdaptiveHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	sample := `
+-------------------------------------
+INSERT BUFFER AND ADAPTIVE HASH INDEX
+-------------------------------------
+Hash table size 34673, node heap has 0 buffer(s)
+12.50 hash searches/s, 3.25 non-hash searches/s
+	`
+	columns := []string{"Type", "Name", "Status"}
+	rows := sqlmock.NewRows(columns).AddRow("InnoDB", "", sample)
+
+	mock.ExpectQuery(sanitizeQuery(engineInnodbStatusQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeEngineInnodbStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricsExpected := []MetricResult{
+		{labels: labelMap{"type": "hash"}, value: 12.5, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"type": "non_hash"}, value: 3.25, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricsExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeEngineInnodbStatusRowOperations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// Wording of MySQL 5.5/5.6.
+	sample := `
+--------------
+ROW OPERATIONS
+--------------
+1 queries inside InnoDB, 0 queries in queue
+Main thread process no. 3032, id 140218046289664, state: waiting for server activity
+Number of rows inserted 0, updated 0, deleted 0, read 12
+	`
+	columns := []string{"Type", "Name", "Status"}
+	rows := sqlmock.NewRows(columns).AddRow("InnoDB", "", sample)
+
+	mock.ExpectQuery(sanitizeQuery(engineInnodbStatusQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeEngineInnodbStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricsExpected := []MetricResult{
+		{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"state": "waiting for server activity"}, value: 1, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricsExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeEngineInnodbStatusInsertBuffer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	sample := `
+-------------------------------------
+INSERT BUFFER AND ADAPTIVE HASH INDEX
+-------------------------------------
+Ibuf: size 7, free list len 0, seg size 9, 40 merges
+merged operations:
+ insert 120, delete mark 30, delete 4
+discarded operations:
+ insert 0, delete mark 0, delete 0
+	`
+	columns := []string{"Type", "Name", "Status"}
+	rows := sqlmock.NewRows(columns).AddRow("InnoDB", "", sample)
+
+	mock.ExpectQuery(sanitizeQuery(engineInnodbStatusQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeEngineInnodbStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	// The discarded operations are not merged ones.
+	metricsExpected := []MetricResult{
+		{labels: labelMap{}, value: 7, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"operation": "insert"}, value: 120, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"operation": "delete_mark"}, value: 30, metricType: dto.MetricType_COUNTER},
+		{labels: labelMap{"operation": "delete"}, value: 4, metricType: dto.MetricType_COUNTER},
 	}
 	convey.Convey("Metrics comparison", t, func() {
 		for _, expect := range metricsExpected {
 			got := readMetric(<-ch)
 			convey.So(got, convey.ShouldResemble, expect)
 		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeEngineInnodbStatusInsertBufferLegacy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// Wording of MySQL 5.1.
+	sample := `
+------------------------------------
+INSERT BUFFER AND ADAPTIVE HASH INDEX
+-------------------------------------
+Ibuf: size 1, free list len 0, seg size 2,
+35 inserts, 35 merged recs, 12 merges
+Hash table size 17393, node heap has 1 buffer(s)
+	`
+	columns := []string{"Type", "Name", "Status"}
+	rows := sqlmock.NewRows(columns).AddRow("InnoDB", "", sample)
+
+	mock.ExpectQuery(sanitizeQuery(engineInnodbStatusQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeEngineInnodbStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricsExpected := []MetricResult{
+		{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"operation": "insert"}, value: 35, metricType: dto.MetricType_COUNTER},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricsExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeEngineInnodbStatusSemaphoresLegacy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// Wording of MySQL 5.5/5.6.
+	sample := `
+----------
+SEMAPHORES
+----------
+OS WAIT ARRAY INFO: reservation count 9, signal count 9
+Mutex spin waits 12, rounds 120, OS waits 3
+RW-shared spins 5, rounds 50, OS waits 2
+RW-excl spins 1, rounds 30, OS waits 1
+Spin rounds per wait: 10.00 mutex, 10.00 RW-shared, 30.00 RW-excl
+	`
+	columns := []string{"Type", "Name", "Status"}
+	rows := sqlmock.NewRows(columns).AddRow("InnoDB", "", sample)
+
+	mock.ExpectQuery(sanitizeQuery(engineInnodbStatusQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeEngineInnodbStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricsExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_innodb_semaphore_spin_waits_total", MetricResult{labels: labelMap{"type": "mutex"}, value: 12, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_spin_rounds_total", MetricResult{labels: labelMap{"type": "mutex"}, value: 120, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_waits_total", MetricResult{labels: labelMap{"type": "mutex"}, value: 3, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_spin_waits_total", MetricResult{labels: labelMap{"type": "rw_shared"}, value: 5, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_spin_rounds_total", MetricResult{labels: labelMap{"type": "rw_shared"}, value: 50, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_waits_total", MetricResult{labels: labelMap{"type": "rw_shared"}, value: 2, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_spin_waits_total", MetricResult{labels: labelMap{"type": "rw_excl"}, value: 1, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_spin_rounds_total", MetricResult{labels: labelMap{"type": "rw_excl"}, value: 30, metricType: dto.MetricType_COUNTER}},
+		{"mysql_innodb_semaphore_waits_total", MetricResult{labels: labelMap{"type": "rw_excl"}, value: 1, metricType: dto.MetricType_COUNTER}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricsExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestInnodbRedoLogCapacity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// As of 8.0.30 innodb_redo_log_capacity supersedes the deprecated variables.
+	columns := []string{"Variable_name", "Value"}
+	mock.ExpectQuery(sanitizeQuery(innodbRedoLogCapacityQuery)).WillReturnRows(
+		sqlmock.NewRows(columns).
+			AddRow("innodb_log_file_size", "50331648").
+			AddRow("innodb_log_files_in_group", "2").
+			AddRow("innodb_redo_log_capacity", "104857600"),
+	)
+	mock.ExpectQuery(sanitizeQuery(innodbRedoLogCapacityQuery)).WillReturnRows(
+		sqlmock.NewRows(columns).
+			AddRow("innodb_log_file_size", "50331648").
+			AddRow("innodb_log_files_in_group", "2"),
+	)
+
+	convey.Convey("Redo log capacity", t, func() {
+		capacity, err := innodbRedoLogCapacity(context.Background(), db)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(capacity, convey.ShouldEqual, 104857600)
+
+		capacity, err = innodbRedoLogCapacity(context.Background(), db)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(capacity, convey.ShouldEqual, 100663296)
 	})
 
 	// Ensure all SQL queries were executed
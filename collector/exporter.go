@@ -16,8 +16,10 @@ package collector
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,7 +27,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
-	_ "github.com/go-sql-driver/mysql"
+	gomysql "github.com/go-sql-driver/mysql"
 	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -39,6 +41,8 @@ const (
 // SQL queries and parameters.
 const (
 	versionQuery = `SELECT @@version`
+	// Whether performance_schema is enabled.
+	performanceSchemaQuery = `SELECT @@performance_schema`
 
 	// System variable params formatting.
 	// See: https://github.com/go-sql-driver/mysql#system-variables
@@ -50,6 +54,9 @@ var (
 	versionRE = regexp.MustCompile(`^\d+\.\d+`)
 )
 
+// Timeout of the checks run once against the server at startup.
+const startupCheckTimeout = 5 * time.Second
+
 // Tunable flags.
 var (
 	exporterLockTimeout = kingpin.Flag(
@@ -60,15 +67,28 @@ var (
 		"exporter.log_slow_filter",
 		"Add a log_slow_filter to avoid slow query logging of scrapes. NOTE: Not supported by Oracle MySQL.",
 	).Default("false").Bool()
+	ignoreVersionGate = kingpin.Flag(
+		"collect.ignore-version-gate",
+		"Run every enabled collector whatever the detected MySQL version, for builds whose version string doesn't parse. Unsupported collectors then fail with scrape errors.",
+	).Default("false").Bool()
+	requiredCollectors = kingpin.Flag(
+		"collect.required",
+		"Comma separated list of collectors, e.g. global_status,global_variables, whose errors fail the whole scrape with a HTTP 500.",
+	).Default("").String()
 )
 
 // Metric descriptors.
 var (
-	scrapeDurationDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, exporter, "collector_duration_seconds"),
+	scrapeDurationDesc = newNamespacedDesc(
+		exporter, "collector_duration_seconds",
 		"Collector time duration.",
 		[]string{"collector"}, nil,
 	)
+	collectorSkippedDesc = newNamespacedDesc(
+		exporter, "collector_skipped",
+		"Whether an enabled collector was skipped during the scrape, with the reason.",
+		[]string{"collector", "reason"}, nil,
+	)
 )
 
 // Verify if Exporter implements prometheus.Collector
@@ -85,27 +105,31 @@ type Exporter struct {
 
 // New returns a new MySQL exporter for the provided DSN.
 func New(ctx context.Context, dsn string, metrics Metrics, scrapers []Scraper, logger log.Logger) *Exporter {
+	return &Exporter{
+		ctx:      ctx,
+		logger:   logger,
+		dsn:      withExporterParams(dsn),
+		scrapers: scrapers,
+		metrics:  metrics,
+	}
+}
+
+// withExporterParams appends the params the exporter connects with to the DSN.
+func withExporterParams(dsn string) string {
 	// Setup extra params for the DSN, default to having a lock timeout.
 	dsnParams := []string{fmt.Sprintf(timeoutParam, *exporterLockTimeout)}
 
 	if *slowLogFilter {
 		dsnParams = append(dsnParams, sessionSettingsParam)
 	}
+	dsnParams = append(dsnParams, driverParams(dsn)...)
 
 	if strings.Contains(dsn, "?") {
 		dsn = dsn + "&"
 	} else {
 		dsn = dsn + "?"
 	}
-	dsn += strings.Join(dsnParams, "&")
-
-	return &Exporter{
-		ctx:      ctx,
-		logger:   logger,
-		dsn:      dsn,
-		scrapers: scrapers,
-		metrics:  metrics,
-	}
+	return dsn + strings.Join(dsnParams, "&")
 }
 
 // Describe implements prometheus.Collector.
@@ -113,16 +137,36 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- e.metrics.TotalScrapes.Desc()
 	ch <- e.metrics.Error.Desc()
 	e.metrics.ScrapeErrors.Describe(ch)
+	e.metrics.QueryErrors.Describe(ch)
+	e.metrics.LastSuccess.Describe(ch)
+	ch <- e.metrics.ScrapesInFlight.Desc()
+	ch <- e.metrics.ScrapesRejected.Desc()
 	ch <- e.metrics.MySQLUp.Desc()
 }
 
 // Collect implements prometheus.Collector.
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
-	e.scrape(e.ctx, ch)
+	if *collectOnlyChanged && e.metrics.gauges != nil {
+		scrapeCh := make(chan prometheus.Metric)
+		done := make(chan struct{})
+		go func() {
+			e.metrics.gauges.forward(e.scrapers, scrapeCh, ch)
+			close(done)
+		}()
+		e.scrape(e.ctx, scrapeCh)
+		close(scrapeCh)
+		<-done
+	} else {
+		e.scrape(e.ctx, ch)
+	}
 
 	ch <- e.metrics.TotalScrapes
 	ch <- e.metrics.Error
 	e.metrics.ScrapeErrors.Collect(ch)
+	e.metrics.QueryErrors.Collect(ch)
+	e.metrics.LastSuccess.Collect(ch)
+	ch <- e.metrics.ScrapesInFlight
+	ch <- e.metrics.ScrapesRejected
 	ch <- e.metrics.MySQLUp
 }
 
@@ -131,9 +175,10 @@ func (e *Exporter) scrape(ctx context.Context, ch chan<- prometheus.Metric) {
 	var err error
 
 	scrapeTime := time.Now()
-	db, err := sql.Open("mysql", e.dsn)
+	db, err := openDB(e.dsn)
 	if err != nil {
 		level.Error(e.logger).Log("msg", "Error opening connection to database", "err", err)
+		e.recordError("connection", err)
 		e.metrics.Error.Set(1)
 		return
 	}
@@ -147,6 +192,7 @@ func (e *Exporter) scrape(ctx context.Context, ch chan<- prometheus.Metric) {
 
 	if err := db.PingContext(ctx); err != nil {
 		level.Error(e.logger).Log("msg", "Error pinging mysqld", "err", err)
+		e.recordError("connection", err)
 		e.metrics.MySQLUp.Set(0)
 		e.metrics.Error.Set(1)
 		return
@@ -157,29 +203,249 @@ func (e *Exporter) scrape(ctx context.Context, ch chan<- prometheus.Metric) {
 
 	ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, time.Since(scrapeTime).Seconds(), "connection")
 
+	e.runScrapers(ctx, db, ch)
+}
+
+// runScrapers runs the scrapers which the server supports and reports the
+// others as skipped.
+func (e *Exporter) runScrapers(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric) {
 	version := getMySQLVersion(db, e.logger)
+	perfSchemaEnabled := !needsPerformanceSchema(e.scrapers) || performanceSchemaStatus.enabled(ctx, db, e.logger)
+	required := parseRequiredCollectors(*requiredCollectors)
+	scrapers := e.scrapers
+	if *combineStatusVariables && multiStatementsEnabled(e.dsn) {
+		scrapers = combineGlobalStatusVariables(scrapers)
+		required[ScrapeGlobalStatusVariables{}.Name()] = required[globalStatus] || required[globalVariables]
+	}
 	var wg sync.WaitGroup
 	defer wg.Wait()
-	for _, scraper := range e.scrapers {
-		if version < scraper.Version() {
+	for _, scraper := range scrapers {
+		if reason := skipReason(scraper, version, perfSchemaEnabled); reason != "" {
+			ch <- prometheus.MustNewConstMetric(collectorSkippedDesc, prometheus.GaugeValue, 1, "collect."+scraper.Name(), reason)
 			continue
 		}
 
 		wg.Add(1)
 		go func(scraper Scraper) {
 			defer wg.Done()
-			label := "collect." + scraper.Name()
-			scrapeTime := time.Now()
-			if err := scraper.Scrape(ctx, db, ch, log.With(e.logger, "scraper", scraper.Name())); err != nil {
-				level.Error(e.logger).Log("msg", "Error from scraper", "scraper", scraper.Name(), "err", err)
-				e.metrics.ScrapeErrors.WithLabelValues(label).Inc()
-				e.metrics.Error.Set(1)
-			}
-			ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, time.Since(scrapeTime).Seconds(), label)
+			e.runScraper(ctx, db, ch, scraper, required[scraper.Name()])
 		}(scraper)
 	}
 }
 
+// skipReason returns why the scraper can't run against the server, or an
+// empty string if it can.
+func skipReason(scraper Scraper, version float64, perfSchemaEnabled bool) string {
+	if !*ignoreVersionGate && version < scraper.Version() {
+		return "version"
+	}
+	if !perfSchemaEnabled && usesPerformanceSchema(scraper) {
+		return "performance_schema_disabled"
+	}
+	return ""
+}
+
+// runScraper runs one scraper and records its outcome. The error of a
+// required scraper is also sent as an invalid metric, which fails the scrape.
+func (e *Exporter) runScraper(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, scraper Scraper, required bool) {
+	label := "collect." + scraper.Name()
+	// All logs of a scraper go through the same logger, so they share format and context.
+	logger := log.With(e.logger, "scraper", scraper.Name())
+	scrapeTime := time.Now()
+	ctx = withQueryAnnotation(ctx, scraper.Name())
+	if err := scraper.Scrape(ctx, db, ch, logger); err != nil {
+		level.Error(logger).Log("msg", "Error from scraper", "err", err)
+		e.recordError(label, err)
+		e.metrics.ScrapeErrors.WithLabelValues(label).Inc()
+		e.metrics.QueryErrors.WithLabelValues(label, queryErrorNumber(err)).Inc()
+		e.metrics.Error.Set(1)
+		if required {
+			err = fmt.Errorf("required collector %s failed: %w", scraper.Name(), err)
+			ch <- prometheus.NewInvalidMetric(prometheus.NewInvalidDesc(err), err)
+		}
+	} else {
+		e.metrics.LastSuccess.WithLabelValues(label).SetToCurrentTime()
+	}
+	ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, time.Since(scrapeTime).Seconds(), label)
+}
+
+// recordError keeps the error for /debug/errors when enabled.
+func (e *Exporter) recordError(collector string, err error) {
+	if e.metrics.RecentErrors != nil {
+		e.metrics.RecentErrors.Add(collector, err)
+	}
+}
+
+// queryErrorNumber returns the MySQL error number of a scraper error,
+// "timeout" when the scrape ran out of time, or "unknown" otherwise.
+func queryErrorNumber(err error) string {
+	var mysqlErr *gomysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return strconv.Itoa(int(mysqlErr.Number))
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// CheckRequiredCollectors returns an error if --collect.required names a
+// collector which is unknown or not enabled, its errors would go unnoticed.
+func CheckRequiredCollectors(scrapers []Scraper) error {
+	enabled := make(map[string]bool, len(scrapers))
+	for _, scraper := range scrapers {
+		enabled[scraper.Name()] = true
+	}
+	var missing []string
+	for name := range parseRequiredCollectors(*requiredCollectors) {
+		if !enabled[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("required collectors are unknown or disabled: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// parseRequiredCollectors returns the set of collector names in a comma separated list.
+func parseRequiredCollectors(list string) map[string]bool {
+	required := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+// LogSkippedScrapers connects to the server once and logs the enabled scrapers
+// that will be skipped because the server version is older than they require
+// or because performance_schema is disabled. It also warns when the DSN
+// doesn't allow the combined global status and variables query.
+func LogSkippedScrapers(dsn string, scrapers []Scraper, logger log.Logger) {
+	db, err := openDB(dsn)
+	if err != nil {
+		level.Warn(logger).Log("msg", "Error opening connection to database", "err", err)
+		return
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupCheckTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		level.Warn(logger).Log("msg", "Unable to check scrapers against the server version", "err", err)
+		return
+	}
+	logSkippedScrapers(ctx, db, dsn, scrapers, logger)
+}
+
+func logSkippedScrapers(ctx context.Context, db *sql.DB, dsn string, scrapers []Scraper, logger log.Logger) {
+	version := getMySQLVersion(db, logger)
+	for _, scraper := range scrapers {
+		if !*ignoreVersionGate && version < scraper.Version() {
+			level.Warn(logger).Log("msg", "Scraper skipped, server version is too old", "scraper", scraper.Name(), "version", version, "required_version", scraper.Version())
+		}
+	}
+	if needsPerformanceSchema(scrapers) && !performanceSchemaStatus.enabled(ctx, db, logger) {
+		level.Warn(logger).Log("msg", "performance_schema is disabled, all perf_schema scrapers will be skipped")
+	}
+	if *combineStatusVariables && !multiStatementsEnabled(dsn) {
+		level.Warn(logger).Log("msg", "multiStatements=true is missing from the DSN, global_status and global_variables will be collected separately")
+	}
+}
+
+// CheckDSN connects to the server the same way the scrapes do and returns
+// an error if that fails.
+func CheckDSN(ctx context.Context, dsn string) error {
+	db, err := openDB(withExporterParams(dsn))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.PingContext(ctx)
+}
+
+// ShardLabelValue runs the query on a new connection and returns the first
+// column of its first row as a valid label value, or an empty string if
+// there are no rows.
+func ShardLabelValue(ctx context.Context, dsn string, query string) (string, error) {
+	db, err := openDB(dsn)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+	value, err := queryFirstValue(ctx, db, query)
+	return labelValue(value, 0), err
+}
+
+func queryFirstValue(ctx context.Context, db *sql.DB, query string) (string, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() || len(columns) == 0 {
+		return "", rows.Err()
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", err
+	}
+	return values[0].String, nil
+}
+
+// usesPerformanceSchema tells whether the scraper reads from performance_schema.
+func usesPerformanceSchema(scraper Scraper) bool {
+	return strings.HasPrefix(scraper.Name(), performanceSchema+".")
+}
+
+func needsPerformanceSchema(scrapers []Scraper) bool {
+	for _, scraper := range scrapers {
+		if usesPerformanceSchema(scraper) {
+			return true
+		}
+	}
+	return false
+}
+
+// performanceSchemaStatus keeps whether performance_schema is enabled. It
+// can't change without a server restart, so the server is only asked once,
+// at startup or on the first scrape.
+var performanceSchemaStatus = &perfSchemaStatus{}
+
+type perfSchemaStatus struct {
+	mu      sync.Mutex
+	checked bool
+	on      bool
+}
+
+// enabled reports whether performance_schema is enabled. If it can't be
+// determined, it is assumed to be enabled so scrapers report their own
+// errors, and it is asked again next time.
+func (s *perfSchemaStatus) enabled(ctx context.Context, db *sql.DB, logger log.Logger) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.checked {
+		if err := db.QueryRowContext(ctx, performanceSchemaQuery).Scan(&s.on); err != nil {
+			level.Debug(logger).Log("msg", "Error querying performance_schema status", "err", err)
+			return true
+		}
+		s.checked = true
+	}
+	return s.on
+}
+
 func getMySQLVersion(db *sql.DB, logger log.Logger) float64 {
 	var versionStr string
 	var versionNum float64
@@ -198,15 +464,36 @@ func getMySQLVersion(db *sql.DB, logger log.Logger) float64 {
 
 // Metrics represents exporter metrics which values can be carried between http requests.
 type Metrics struct {
-	TotalScrapes prometheus.Counter
-	ScrapeErrors *prometheus.CounterVec
-	Error        prometheus.Gauge
-	MySQLUp      prometheus.Gauge
+	TotalScrapes    prometheus.Counter
+	ScrapeErrors    *prometheus.CounterVec
+	QueryErrors     *prometheus.CounterVec
+	LastSuccess     *prometheus.GaugeVec
+	ScrapesInFlight prometheus.Gauge
+	ScrapesRejected prometheus.Counter
+	Error           prometheus.Gauge
+	MySQLUp         prometheus.Gauge
+	// RecentErrors keeps the latest scrape errors, nil when disabled.
+	RecentErrors *RecentErrors
+
+	// gauges holds the last scraped gauge values for --collect.only-changed.
+	gauges *gaugeValues
+}
+
+// WithoutOnlyChanged returns a copy of the metrics whose scrapes bypass
+// --collect.only-changed and don't update its last values, e.g. for a
+// warmup collection whose output is thrown away.
+func (m Metrics) WithoutOnlyChanged() Metrics {
+	m.gauges = nil
+	return m
 }
 
 // NewMetrics creates new Metrics instance.
 func NewMetrics() Metrics {
 	subsystem := exporter
+	var recentErrors *RecentErrors
+	if *recentErrorsSize > 0 {
+		recentErrors = NewRecentErrors(*recentErrorsSize)
+	}
 	return Metrics{
 		TotalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: namespace,
@@ -220,6 +507,30 @@ func NewMetrics() Metrics {
 			Name:      "scrape_errors_total",
 			Help:      "Total number of times an error occurred scraping a MySQL.",
 		}, []string{"collector"}),
+		QueryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "query_errors_total",
+			Help:      "Total number of collector errors by MySQL error number, \"timeout\" when the scrape timed out and \"unknown\" for other errors.",
+		}, []string{"collector", "error_number"}),
+		LastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "collector_last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful scrape of each collector.",
+		}, []string{"collector"}),
+		ScrapesInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "scrapes_in_flight",
+			Help:      "Number of scrapes currently collecting from MySQL.",
+		}),
+		ScrapesRejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "scrapes_rejected_total",
+			Help:      "Total number of scrapes rejected because too many scrapes were already running.",
+		}),
 		Error: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
@@ -231,5 +542,7 @@ func NewMetrics() Metrics {
 			Name:      "up",
 			Help:      "Whether the MySQL server is up.",
 		}),
+		RecentErrors: recentErrors,
+		gauges:       newGaugeValues(),
 	}
 }
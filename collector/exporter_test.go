@@ -14,16 +14,24 @@
 package collector
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"os"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	gomysql "github.com/go-sql-driver/mysql"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/model"
 	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const dsn = "root@/mysql"
@@ -86,3 +94,244 @@ func TestGetMySQLVersion(t *testing.T) {
 		convey.So(getMySQLVersion(db, logger), convey.ShouldBeBetweenOrEqual, 5.6, 10.5)
 	})
 }
+
+func TestPerformanceSchemaStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(sanitizeQuery(performanceSchemaQuery)).WillReturnError(errors.New("unknown system variable"))
+	mock.ExpectQuery(sanitizeQuery(performanceSchemaQuery)).WillReturnRows(sqlmock.NewRows([]string{"@@performance_schema"}).AddRow("0"))
+
+	status := &perfSchemaStatus{}
+	convey.Convey("performance_schema status", t, func() {
+		// Errors don't disable the scrapers and aren't kept.
+		convey.So(status.enabled(context.Background(), db, log.NewNopLogger()), convey.ShouldBeTrue)
+		convey.So(status.enabled(context.Background(), db, log.NewNopLogger()), convey.ShouldBeFalse)
+		// The status is only queried once.
+		convey.So(status.enabled(context.Background(), db, log.NewNopLogger()), convey.ShouldBeFalse)
+
+		convey.So(needsPerformanceSchema([]Scraper{ScrapeGlobalStatus{}}), convey.ShouldBeFalse)
+		convey.So(needsPerformanceSchema([]Scraper{ScrapeGlobalStatus{}, ScrapePerfEventsTransactions{}}), convey.ShouldBeTrue)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestQueryFirstValue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	const query = "SELECT keyspace, shard FROM meta.shard"
+	mock.ExpectQuery(sanitizeQuery(query)).WillReturnRows(sqlmock.NewRows([]string{"keyspace", "shard"}).AddRow("commerce", "-80"))
+	mock.ExpectQuery(sanitizeQuery(query)).WillReturnRows(sqlmock.NewRows([]string{"keyspace", "shard"}))
+	mock.ExpectQuery(sanitizeQuery(query)).WillReturnRows(sqlmock.NewRows([]string{"keyspace", "shard"}).AddRow(nil, "-80"))
+
+	convey.Convey("First value of a query", t, func() {
+		value, err := queryFirstValue(context.Background(), db, query)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(value, convey.ShouldEqual, "commerce")
+
+		// No rows and NULL give no value.
+		value, err = queryFirstValue(context.Background(), db, query)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(value, convey.ShouldEqual, "")
+		value, err = queryFirstValue(context.Background(), db, query)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(value, convey.ShouldEqual, "")
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+// failingScraper always fails.
+type failingScraper struct{ ScrapeGlobalStatus }
+
+func (failingScraper) Scrape(context.Context, *sql.DB, chan<- prometheus.Metric, log.Logger) error {
+	return errors.New("access denied")
+}
+
+func TestRunScraperRequired(t *testing.T) {
+	convey.Convey("Scraper errors", t, func() {
+		for _, required := range []bool{false, true} {
+			e := &Exporter{logger: log.NewNopLogger(), metrics: NewMetrics()}
+			ch := make(chan prometheus.Metric)
+			go func() {
+				e.runScraper(context.Background(), nil, ch, failingScraper{}, required)
+				close(ch)
+			}()
+
+			var invalid int
+			for m := range ch {
+				if err := m.Write(&dto.Metric{}); err != nil {
+					convey.So(err.Error(), convey.ShouldContainSubstring, "required collector global_status failed: access denied")
+					invalid++
+				}
+			}
+			// Only required collectors fail the scrape.
+			if required {
+				convey.So(invalid, convey.ShouldEqual, 1)
+			} else {
+				convey.So(invalid, convey.ShouldEqual, 0)
+			}
+		}
+	})
+}
+
+func TestQueryErrorNumber(t *testing.T) {
+	convey.Convey("Query error numbers", t, func() {
+		convey.So(queryErrorNumber(&gomysql.MySQLError{Number: 1045, Message: "Access denied"}), convey.ShouldEqual, "1045")
+		convey.So(queryErrorNumber(fmt.Errorf("scraping: %w", &gomysql.MySQLError{Number: 1146})), convey.ShouldEqual, "1146")
+		convey.So(queryErrorNumber(context.DeadlineExceeded), convey.ShouldEqual, "timeout")
+		convey.So(queryErrorNumber(errors.New("access denied")), convey.ShouldEqual, "unknown")
+	})
+}
+
+func TestRunScraperQueryErrors(t *testing.T) {
+	convey.Convey("Query errors", t, func() {
+		e := &Exporter{logger: log.NewNopLogger(), metrics: NewMetrics()}
+		ch := make(chan prometheus.Metric)
+		go func() {
+			e.runScraper(context.Background(), nil, ch, failingScraper{}, false)
+			close(ch)
+		}()
+		for range ch {
+		}
+		convey.So(testutil.ToFloat64(e.metrics.QueryErrors.WithLabelValues("collect.global_status", "unknown")), convey.ShouldEqual, 1)
+	})
+}
+
+func TestParseRequiredCollectors(t *testing.T) {
+	convey.Convey("Required collectors", t, func() {
+		convey.So(parseRequiredCollectors(""), convey.ShouldBeEmpty)
+		convey.So(parseRequiredCollectors("global_status, global_variables"), convey.ShouldResemble, map[string]bool{"global_status": true, "global_variables": true})
+	})
+}
+
+func TestCheckRequiredCollectors(t *testing.T) {
+	defer kingpin.CommandLine.Parse([]string{})
+
+	convey.Convey("Required collectors check", t, func() {
+		scrapers := []Scraper{ScrapeGlobalStatus{}, ScrapeGlobalVariables{}}
+		convey.So(CheckRequiredCollectors(scrapers), convey.ShouldBeNil)
+
+		_, err := kingpin.CommandLine.Parse([]string{"--collect.required=global_status,global_variables"})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(CheckRequiredCollectors(scrapers), convey.ShouldBeNil)
+
+		_, err = kingpin.CommandLine.Parse([]string{"--collect.required=global_status,slave_status,global_statuss"})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(CheckRequiredCollectors(scrapers), convey.ShouldBeError, "required collectors are unknown or disabled: global_statuss, slave_status")
+	})
+}
+
+func TestRunScrapersSkipped(t *testing.T) {
+	defer func() { performanceSchemaStatus = &perfSchemaStatus{} }()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(sanitizeQuery(versionQuery)).WillReturnRows(sqlmock.NewRows([]string{"@@version"}).AddRow("5.7.33-log"))
+	mock.ExpectQuery(sanitizeQuery(performanceSchemaQuery)).WillReturnRows(sqlmock.NewRows([]string{"@@performance_schema"}).AddRow("0"))
+
+	exporter := &Exporter{
+		ctx:      context.Background(),
+		logger:   log.NewNopLogger(),
+		scrapers: []Scraper{ScrapeInfoSchemaInnodbUndo{}, ScrapePerfTableIOWaits{}},
+		metrics:  NewMetrics(),
+	}
+	ch := make(chan prometheus.Metric)
+	go func() {
+		exporter.runScrapers(context.Background(), db, ch)
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"collector": "collect.info_schema.innodb_undo", "reason": "version"}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"collector": "collect.perf_schema.tableiowaits", "reason": "performance_schema_disabled"}, value: 1, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Skipped collectors", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"mysql_exporter_collector_skipped"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestLogSkippedScrapers(t *testing.T) {
+	defer func() { performanceSchemaStatus = &perfSchemaStatus{} }()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(sanitizeQuery(versionQuery)).WillReturnRows(sqlmock.NewRows([]string{"@@version"}).AddRow("5.7.33-log"))
+	mock.ExpectQuery(sanitizeQuery(performanceSchemaQuery)).WillReturnRows(sqlmock.NewRows([]string{"@@performance_schema"}).AddRow("0"))
+
+	var buf bytes.Buffer
+	scrapers := []Scraper{ScrapeGlobalStatus{}, ScrapeInfoSchemaInnodbUndo{}, ScrapePerfTableIOWaits{}}
+	logSkippedScrapers(context.Background(), db, dsn, scrapers, log.NewLogfmtLogger(&buf))
+
+	convey.Convey("Skipped scrapers are logged", t, func() {
+		logged := buf.String()
+		convey.So(logged, convey.ShouldContainSubstring, "scraper=info_schema.innodb_undo version=5.7 required_version=8")
+		convey.So(logged, convey.ShouldContainSubstring, "performance_schema is disabled")
+		convey.So(logged, convey.ShouldNotContainSubstring, "scraper=global_status")
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestSkipReason(t *testing.T) {
+	convey.Convey("Skip reasons", t, func() {
+		convey.So(skipReason(ScrapeInfoSchemaInnodbUndo{}, 8.0, true), convey.ShouldEqual, "")
+		convey.So(skipReason(ScrapeInfoSchemaInnodbUndo{}, 5.7, true), convey.ShouldEqual, "version")
+		convey.So(skipReason(ScrapePerfTableIOWaits{}, 8.0, false), convey.ShouldEqual, "performance_schema_disabled")
+
+		_, err := kingpin.CommandLine.Parse([]string{"--collect.ignore-version-gate"})
+		convey.So(err, convey.ShouldBeNil)
+		defer kingpin.CommandLine.Parse([]string{})
+		convey.So(skipReason(ScrapeInfoSchemaInnodbUndo{}, 5.7, true), convey.ShouldEqual, "")
+		convey.So(skipReason(ScrapePerfTableIOWaits{}, 5.5, false), convey.ShouldEqual, "performance_schema_disabled")
+	})
+}
+
+func TestWithExporterParams(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--mysqld.timeout=5s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	convey.Convey("Exporter DSN params", t, func() {
+		convey.So(withExporterParams("user:pass@tcp(localhost:3306)/"), convey.ShouldEqual, "user:pass@tcp(localhost:3306)/?lock_wait_timeout=2&timeout=5s")
+		convey.So(withExporterParams("user:pass@tcp(localhost:3306)/?tls=custom"), convey.ShouldEqual, "user:pass@tcp(localhost:3306)/?tls=custom&lock_wait_timeout=2&timeout=5s")
+	})
+}
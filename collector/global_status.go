@@ -20,9 +20,13 @@ import (
 	"database/sql"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const (
@@ -33,47 +37,337 @@ const (
 )
 
 // Regexp to match various groups of status vars.
-var globalStatusRE = regexp.MustCompile(`^(com|handler|connection_errors|innodb_buffer_pool_pages|innodb_system_rows|innodb_sampled|performance_schema|current_tls|ssl|mysqlx|binlog_stmt_cache)_(.*)$`)
+var globalStatusRE = regexp.MustCompile(`^(com|handler|connection_errors|innodb_buffer_pool_pages|innodb_system_rows|innodb_sampled|performance_schema|current_tls|ssl|mysqlx|binlog_stmt_cache|threadpool|innodb_pages|rpl_semi_sync)_(.*)$`)
+
+// Tunable flags.
+var (
+	globalStatusBinlogCache = kingpin.Flag(
+		"collect.global_status.binlog_cache",
+		"Collect binary log cache usage as mysql_global_status_binlog_cache_total",
+	).Default("false").Bool()
+	globalStatusBufferPoolPagesTotal = kingpin.Flag(
+		"collect.global_status.buffer_pool_pages_total",
+		"Collect Innodb_buffer_pool_pages_total as mysql_global_status_buffer_pool_pages{state=\"total\"}",
+	).Default("false").Bool()
+	globalStatusXA = kingpin.Flag(
+		"collect.global_status.xa",
+		"Collect XA transaction statements as mysql_global_status_com_xa_total",
+	).Default("false").Bool()
+	globalStatusAdminCommands = kingpin.Flag(
+		"collect.global_status.admin_commands",
+		"Collect administrative commands, with all SHOW commands summed as \"show\", in mysql_global_status_commands_total",
+	).Default("false").Bool()
+	globalStatusEmitDeltas = kingpin.Flag(
+		"collect.emit-deltas",
+		"Comma separated list of status variables to also collect as the change since the previous scrape, in mysql_global_status_<name>_delta. Non-standard, rate() is preferred",
+	).Default("").String()
+	globalStatusServeStale = kingpin.Flag(
+		"collect.global_status.serve-stale",
+		"Maximum age of the last successful global status values served when the query fails, 0 to disable",
+	).Default("0s").Duration()
+	globalStatusDDL = kingpin.Flag(
+		"collect.global_status.ddl",
+		"Collect schema changes and statement re-preparations in mysql_global_status_commands_total",
+	).Default("false").Bool()
+)
+
+// binlogCacheStatus maps the binary log cache status variables to their type and location labels.
+var binlogCacheStatus = map[string][2]string{
+	"binlog_cache_use":           {"transactional", "all"},
+	"binlog_cache_disk_use":      {"transactional", "disk"},
+	"binlog_stmt_cache_use":      {"non_transactional", "all"},
+	"binlog_stmt_cache_disk_use": {"non_transactional", "disk"},
+}
 
 // Metric descriptors.
 var (
-	globalCommandsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, globalStatus, "commands_total"),
+	globalCommandsDesc = newNamespacedDesc(
+		globalStatus, "commands_total",
 		"Total number of executed MySQL commands.",
 		[]string{"command"}, nil,
 	)
-	globalHandlerDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, globalStatus, "handlers_total"),
+	globalHandlerDesc = newNamespacedDesc(
+		globalStatus, "handlers_total",
 		"Total number of executed MySQL handlers.",
 		[]string{"handler"}, nil,
 	)
-	globalConnectionErrorsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, globalStatus, "connection_errors_total"),
+	globalConnectionErrorsDesc = newNamespacedDesc(
+		globalStatus, "connection_errors_total",
 		"Total number of MySQL connection errors.",
 		[]string{"error"}, nil,
 	)
-	globalBufferPoolPagesDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, globalStatus, "buffer_pool_pages"),
+	globalBufferPoolPagesDesc = newNamespacedDesc(
+		globalStatus, "buffer_pool_pages",
 		"Innodb buffer pool pages by state.",
 		[]string{"state"}, nil,
 	)
-	globalBufferPoolDirtyPagesDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, globalStatus, "buffer_pool_dirty_pages"),
+	globalBufferPoolDirtyPagesDesc = newNamespacedDesc(
+		globalStatus, "buffer_pool_dirty_pages",
 		"Innodb buffer pool dirty pages.",
 		[]string{}, nil,
 	)
-	globalBufferPoolPageChangesDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, globalStatus, "buffer_pool_page_changes_total"),
+	globalBufferPoolPageChangesDesc = newNamespacedDesc(
+		globalStatus, "buffer_pool_page_changes_total",
 		"Innodb buffer pool page state changes.",
 		[]string{"operation"}, nil,
 	)
-	globalInnoDBRowOpsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, globalStatus, "innodb_row_ops_total"),
+	globalInnoDBRowOpsDesc = newNamespacedDesc(
+		globalStatus, "innodb_row_ops_total",
 		"Total number of MySQL InnoDB row operations.",
 		[]string{"operation"}, nil,
 	)
+	globalBufferPoolReadAheadDesc = newNamespacedDesc(
+		globalStatus, "innodb_buffer_pool_read_ahead_total",
+		"Innodb buffer pool pages read by the read-ahead background thread by type (linear, random, evicted without access).",
+		[]string{"type"}, nil,
+	)
+	globalInnoDBPagesDesc = newNamespacedDesc(
+		globalStatus, "innodb_pages_total",
+		"Total number of InnoDB pages created, read or written.",
+		[]string{"operation"}, nil,
+	)
+	globalConnectionControlDelayGeneratedDesc = newNamespacedDesc(
+		globalStatus, "connection_control_delay_generated_total",
+		"Total number of failed connection attempts delayed by the connection_control plugin.",
+		nil, nil,
+	)
+	globalBufferPoolWaitFreeDesc = newNamespacedDesc(
+		globalStatus, "innodb_buffer_pool_wait_free_total",
+		"Total number of times a thread had to wait for InnoDB buffer pool pages to be flushed before reading or creating a page.",
+		nil, nil,
+	)
+	globalBufferPoolPagesFlushedDesc = newNamespacedDesc(
+		globalStatus, "innodb_buffer_pool_pages_flushed_total",
+		"Total number of requests to flush pages from the InnoDB buffer pool.",
+		nil, nil,
+	)
+	globalOngoingAnonymousTransactionsDesc = newNamespacedDesc(
+		globalStatus, "ongoing_anonymous_transaction_count",
+		"Number of ongoing transactions without a GTID, which break GTID based failover.",
+		nil, nil,
+	)
+	globalSlaveLastHeartbeatDesc = newNamespacedDesc(
+		globalStatus, "slave_last_heartbeat_timestamp_seconds",
+		"Unix timestamp of the last replication heartbeat received by the replica.",
+		nil, nil,
+	)
+	globalThreadpoolThreadsDesc = newNamespacedDesc(
+		globalStatus, "threadpool_threads",
+		"Number of threads in the thread pool by state.",
+		[]string{"state"}, nil,
+	)
+	globalOpenFilesDesc = newNamespacedDesc(
+		globalStatus, "open_files",
+		"Number of files that are open, to compare with the open_files_limit variable.",
+		nil, nil,
+	)
+	globalOpenStreamsDesc = newNamespacedDesc(
+		globalStatus, "open_streams",
+		"Number of streams that are open, used mainly for logging.",
+		nil, nil,
+	)
+	globalInnoDBNumOpenFilesDesc = newNamespacedDesc(
+		globalStatus, "innodb_num_open_files",
+		"Number of files InnoDB currently holds open.",
+		nil, nil,
+	)
+	globalBufferPoolReadsDesc = newNamespacedDesc(
+		globalStatus, "innodb_buffer_pool_reads_total",
+		"Total number of logical reads that InnoDB could not satisfy from the buffer pool and read from disk. Hit ratio: 1 - rate(mysql_global_status_innodb_buffer_pool_reads_total[5m]) / rate(mysql_global_status_innodb_buffer_pool_read_requests_total[5m]).",
+		nil, nil,
+	)
+	globalBufferPoolReadRequestsDesc = newNamespacedDesc(
+		globalStatus, "innodb_buffer_pool_read_requests_total",
+		"Total number of logical read requests to the InnoDB buffer pool.",
+		nil, nil,
+	)
+	globalPreparedStatementsDesc = newNamespacedDesc(
+		globalStatus, "prepared_statements",
+		"Current number of prepared statements, a steady growth hints at statements never closed.",
+		nil, nil,
+	)
+	globalAclCacheItemsDesc = newNamespacedDesc(
+		globalStatus, "acl_cache_items_count",
+		"Number of cached privilege objects, grows with the number of accounts.",
+		nil, nil,
+	)
+	globalTableLocksDesc = newNamespacedDesc(
+		globalStatus, "table_locks_total",
+		"Total number of table lock requests by status, a rising share of waited ones signals lock contention.",
+		[]string{"status"}, nil,
+	)
+	globalInnoDBDeadlocksDesc = newNamespacedDesc(
+		globalStatus, "innodb_deadlocks_total",
+		"Total number of InnoDB deadlocks, Percona Server only.",
+		nil, nil,
+	)
+	globalInnoDBLockTimeoutsDesc = newNamespacedDesc(
+		globalStatus, "innodb_lock_timeouts_total",
+		"Total number of InnoDB row lock wait timeouts, Percona Server only.",
+		nil, nil,
+	)
+	globalComXADesc = newNamespacedDesc(
+		globalStatus, "com_xa_total",
+		"Total number of XA transaction statements, prepared transactions not committed nor rolled back keep holding their locks.",
+		[]string{"operation"}, nil,
+	)
+	globalComStmtDesc = newNamespacedDesc(
+		globalStatus, "com_stmt_total",
+		"Total number of prepared statement operations.",
+		[]string{"operation"}, nil,
+	)
+	globalSlowQueriesDesc = newNamespacedDesc(
+		globalStatus, "slow_queries_total",
+		"Total number of queries that took more than long_query_time seconds.",
+		nil, nil,
+	)
+	globalQuestionsDesc = newNamespacedDesc(
+		globalStatus, "questions_total",
+		"Total number of statements sent by clients, not counting the statements run within stored programs.",
+		nil, nil,
+	)
+	globalQueriesDesc = newNamespacedDesc(
+		globalStatus, "queries_total",
+		"Total number of statements executed, including the statements run within stored programs.",
+		nil, nil,
+	)
+	globalStatusStaleDesc = newNamespacedDesc(
+		globalStatus, "stale",
+		"Whether the global status values are those of an earlier scrape as the query failed (1 for stale, 0 for fresh).",
+		nil, nil,
+	)
+	globalConnectionsDesc = newNamespacedDesc(
+		globalStatus, "connections_total",
+		"Total number of connection attempts, successful or not.",
+		nil, nil,
+	)
+	globalThreadsCreatedDesc = newNamespacedDesc(
+		globalStatus, "threads_created_total",
+		"Total number of threads created to handle connections. Divided by the connection rate, rate(mysql_global_status_threads_created_total[5m]) / rate(mysql_global_status_connections_total[5m]) is the thread cache miss rate, the thread_cache_size is too small when it rises.",
+		nil, nil,
+	)
+	globalBinlogCacheDesc = newNamespacedDesc(
+		globalStatus, "binlog_cache_total",
+		"Total number of transactions that used the binary log cache by type, location \"disk\" counts those that spilled to a temporary file.",
+		[]string{"type", "location"}, nil,
+	)
+	globalSemiSyncMasterStatusDesc = newNamespacedDesc(
+		globalStatus, "rpl_semi_sync_master_status",
+		"Whether semi-synchronous replication is operational on the source (1), or it fell back to asynchronous replication (0).",
+		nil, nil,
+	)
+	globalSemiSyncMasterClientsDesc = newNamespacedDesc(
+		globalStatus, "rpl_semi_sync_master_clients",
+		"Number of semi-synchronous replicas connected to the source.",
+		nil, nil,
+	)
+	globalSemiSyncMasterWaitSessionsDesc = newNamespacedDesc(
+		globalStatus, "rpl_semi_sync_master_wait_sessions",
+		"Number of sessions currently waiting for a replica acknowledgement.",
+		nil, nil,
+	)
+	globalSemiSyncMasterTransactionsDesc = newNamespacedDesc(
+		globalStatus, "rpl_semi_sync_master_transactions_total",
+		"Total number of commits on the source by whether a replica acknowledged them, \"no\" commits were only replicated asynchronously.",
+		[]string{"acknowledged"}, nil,
+	)
+	globalInnoDBPurgeTrxIDAgeDesc = newNamespacedDesc(
+		globalStatus, "innodb_purge_trx_id_age",
+		"Number of transactions not yet purged, the purge lag (Percona Server).",
+		nil, nil,
+	)
+	globalInnoDBPurgeViewTrxIDAgeDesc = newNamespacedDesc(
+		globalStatus, "innodb_purge_view_trx_id_age",
+		"Number of transactions between the oldest read view and the current one, which purge has to wait for (Percona Server).",
+		nil, nil,
+	)
+	globalSemiSyncSlaveStatusDesc = newNamespacedDesc(
+		globalStatus, "rpl_semi_sync_slave_status",
+		"Whether semi-synchronous replication is operational on the replica (1 for on, 0 for off).",
+		nil, nil,
+	)
 )
 
+// adminCommands are the Com_* counters of administrative commands, besides
+// Com_show_* which are summed.
+var adminCommands = map[string]bool{
+	"admin_commands": true,
+	"change_db":      true,
+	"flush":          true,
+	"kill":           true,
+	"set_option":     true,
+}
+
+// ddlCommands are the Com_* counters of schema changes, along with
+// Com_stmt_reprepare which spikes as they invalidate prepared statements.
+var ddlCommands = map[string]bool{
+	"alter_table":    true,
+	"create_index":   true,
+	"drop_index":     true,
+	"stmt_reprepare": true,
+}
+
+// globalStatusDeltas keeps the previous values of the status variables
+// named in --collect.emit-deltas, the exporter scrapes a single server. They
+// are shared by all callers, so concurrent Prometheus servers or collect[]
+// requests each see the change since whichever scrape came last.
+var globalStatusDeltas = &statusDeltas{previous: map[string]float64{}}
+
+type statusDeltas struct {
+	mu       sync.Mutex
+	previous map[string]float64
+}
+
+// delta returns the change of the variable since the previous call, or its
+// current value when it went backwards as the server restarted. It returns
+// false on the first call for the variable.
+func (d *statusDeltas) delta(key string, value float64) (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	previous, ok := d.previous[key]
+	d.previous[key] = value
+	if !ok {
+		return 0, false
+	}
+	if value < previous {
+		return value, true
+	}
+	return value - previous, true
+}
+
+// globalStatusLastGood keeps the metrics of the last successful scrape for
+// --collect.global_status.serve-stale.
+var globalStatusLastGood = &staleStatus{}
+
+type staleStatus struct {
+	mu      sync.Mutex
+	metrics []prometheus.Metric
+	scraped time.Time
+}
+
+// serve sends the fresh metrics and keeps them when the scrape succeeded.
+// Otherwise it sends the kept ones, as long as they are not older than
+// maxAge, and still returns the scrape error.
+func (s *staleStatus) serve(fresh []prometheus.Metric, err error, maxAge time.Duration, ch chan<- prometheus.Metric, logger log.Logger) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stale := 0.0
+	if err == nil {
+		s.metrics, s.scraped = fresh, time.Now()
+	} else {
+		if s.metrics == nil || time.Since(s.scraped) > maxAge {
+			return err
+		}
+		level.Warn(logger).Log("msg", "Serving stale global status", "age", time.Since(s.scraped), "err", err)
+		stale = 1
+	}
+	for _, m := range s.metrics {
+		ch <- m
+	}
+	ch <- prometheus.MustNewConstMetric(globalStatusStaleDesc, prometheus.GaugeValue, stale)
+	return err
+}
+
 // ScrapeGlobalStatus collects from `SHOW GLOBAL STATUS`.
 type ScrapeGlobalStatus struct{}
 
@@ -94,14 +388,60 @@ func (ScrapeGlobalStatus) Version() float64 {
 
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeGlobalStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	return serveGlobalStatus(ch, logger, func(ch chan<- prometheus.Metric) error {
+		return scrapeGlobalStatus(ctx, db, ch)
+	})
+}
+
+// serveGlobalStatus sends the global status metrics of scrape, through
+// globalStatusLastGood when --collect.global_status.serve-stale is set.
+func serveGlobalStatus(ch chan<- prometheus.Metric, logger log.Logger, scrape func(ch chan<- prometheus.Metric) error) error {
+	if *globalStatusServeStale <= 0 {
+		return scrape(ch)
+	}
+
+	// Metrics are only sent once the scrape succeeded, so a failure
+	// halfway through does not mix fresh and stale values.
+	var (
+		fresh   []prometheus.Metric
+		metrics = make(chan prometheus.Metric)
+		done    = make(chan struct{})
+	)
+	go func() {
+		for m := range metrics {
+			fresh = append(fresh, m)
+		}
+		close(done)
+	}()
+	err := scrape(metrics)
+	close(metrics)
+	<-done
+	return globalStatusLastGood.serve(fresh, err, *globalStatusServeStale, ch, logger)
+}
+
+func scrapeGlobalStatus(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric) error {
 	globalStatusRows, err := db.QueryContext(ctx, globalStatusQuery)
 	if err != nil {
 		return err
 	}
 	defer globalStatusRows.Close()
+	return scrapeGlobalStatusRows(globalStatusRows, ch)
+}
 
+// scrapeGlobalStatusRows sends the metrics of the `SHOW GLOBAL STATUS` result.
+func scrapeGlobalStatusRows(globalStatusRows *sql.Rows, ch chan<- prometheus.Metric) error {
 	var key string
 	var val sql.RawBytes
+	var (
+		showCommands    float64
+		hasShowCommands bool
+	)
+	deltaKeys := map[string]bool{}
+	for _, name := range strings.Split(*globalStatusEmitDeltas, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			deltaKeys[validPrometheusName(name)] = true
+		}
+	}
 
 	for globalStatusRows.Next() {
 		if err := globalStatusRows.Scan(&key, &val); err != nil {
@@ -109,6 +449,147 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prom
 		}
 		if floatVal, ok := parseStatus(val); ok { // Unparsable values are silently skipped.
 			key = validPrometheusName(key)
+			if deltaKeys[key] {
+				if delta, ok := globalStatusDeltas.delta(key, floatVal); ok {
+					ch <- prometheus.MustNewConstMetric(
+						newDesc(globalStatus, key+"_delta", "Change of the status variable since the previous scrape."),
+						prometheus.GaugeValue, delta,
+					)
+				}
+			}
+			if labels, ok := binlogCacheStatus[key]; ok && *globalStatusBinlogCache {
+				ch <- prometheus.MustNewConstMetric(
+					globalBinlogCacheDesc, prometheus.CounterValue, floatVal, labels[0], labels[1],
+				)
+				continue
+			}
+			switch key {
+			case "innodb_buffer_pool_read_ahead":
+				ch <- prometheus.MustNewConstMetric(
+					globalBufferPoolReadAheadDesc, prometheus.CounterValue, floatVal, "linear",
+				)
+				continue
+			case "innodb_buffer_pool_read_ahead_rnd":
+				ch <- prometheus.MustNewConstMetric(
+					globalBufferPoolReadAheadDesc, prometheus.CounterValue, floatVal, "random",
+				)
+				continue
+			case "innodb_buffer_pool_read_ahead_evicted":
+				ch <- prometheus.MustNewConstMetric(
+					globalBufferPoolReadAheadDesc, prometheus.CounterValue, floatVal, "evicted",
+				)
+				continue
+			case "innodb_buffer_pool_reads":
+				ch <- prometheus.MustNewConstMetric(
+					globalBufferPoolReadsDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "innodb_buffer_pool_read_requests":
+				ch <- prometheus.MustNewConstMetric(
+					globalBufferPoolReadRequestsDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "innodb_buffer_pool_wait_free":
+				ch <- prometheus.MustNewConstMetric(
+					globalBufferPoolWaitFreeDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "ongoing_anonymous_transaction_count":
+				ch <- prometheus.MustNewConstMetric(
+					globalOngoingAnonymousTransactionsDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "slave_last_heartbeat":
+				ch <- prometheus.MustNewConstMetric(
+					globalSlaveLastHeartbeatDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "open_files":
+				ch <- prometheus.MustNewConstMetric(
+					globalOpenFilesDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "open_streams":
+				ch <- prometheus.MustNewConstMetric(
+					globalOpenStreamsDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "innodb_num_open_files":
+				ch <- prometheus.MustNewConstMetric(
+					globalInnoDBNumOpenFilesDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "prepared_stmt_count":
+				ch <- prometheus.MustNewConstMetric(
+					globalPreparedStatementsDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "acl_cache_items_count":
+				ch <- prometheus.MustNewConstMetric(
+					globalAclCacheItemsDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "table_locks_immediate":
+				ch <- prometheus.MustNewConstMetric(
+					globalTableLocksDesc, prometheus.CounterValue, floatVal, "immediate",
+				)
+				continue
+			case "table_locks_waited":
+				ch <- prometheus.MustNewConstMetric(
+					globalTableLocksDesc, prometheus.CounterValue, floatVal, "waited",
+				)
+				continue
+			case "innodb_deadlocks":
+				ch <- prometheus.MustNewConstMetric(
+					globalInnoDBDeadlocksDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "innodb_lock_timeouts":
+				ch <- prometheus.MustNewConstMetric(
+					globalInnoDBLockTimeoutsDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "slow_queries":
+				ch <- prometheus.MustNewConstMetric(
+					globalSlowQueriesDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "questions":
+				ch <- prometheus.MustNewConstMetric(
+					globalQuestionsDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "queries":
+				ch <- prometheus.MustNewConstMetric(
+					globalQueriesDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "connections":
+				ch <- prometheus.MustNewConstMetric(
+					globalConnectionsDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "threads_created":
+				ch <- prometheus.MustNewConstMetric(
+					globalThreadsCreatedDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			case "innodb_purge_trx_id_age":
+				ch <- prometheus.MustNewConstMetric(
+					globalInnoDBPurgeTrxIDAgeDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "innodb_purge_view_trx_id_age":
+				ch <- prometheus.MustNewConstMetric(
+					globalInnoDBPurgeViewTrxIDAgeDesc, prometheus.GaugeValue, floatVal,
+				)
+				continue
+			case "connection_control_delay_generated":
+				ch <- prometheus.MustNewConstMetric(
+					globalConnectionControlDelayGeneratedDesc, prometheus.CounterValue, floatVal,
+				)
+				continue
+			}
 			match := globalStatusRE.FindStringSubmatch(key)
 			if match == nil {
 				ch <- prometheus.MustNewConstMetric(
@@ -125,8 +606,34 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prom
 					ch <- prometheus.MustNewConstMetric(
 						globalCommandsDesc, prometheus.CounterValue, floatVal, match[2],
 					)
+				case "stmt_prepare", "stmt_execute", "stmt_close":
+					ch <- prometheus.MustNewConstMetric(
+						globalComStmtDesc, prometheus.CounterValue, floatVal, strings.TrimPrefix(match[2], "stmt_"),
+					)
 				default:
-					continue
+					if *globalStatusXA && strings.HasPrefix(match[2], "xa_") {
+						ch <- prometheus.MustNewConstMetric(
+							globalComXADesc, prometheus.CounterValue, floatVal, strings.TrimPrefix(match[2], "xa_"),
+						)
+						continue
+					}
+					if *globalStatusDDL && ddlCommands[match[2]] {
+						ch <- prometheus.MustNewConstMetric(
+							globalCommandsDesc, prometheus.CounterValue, floatVal, match[2],
+						)
+						continue
+					}
+					if !*globalStatusAdminCommands {
+						continue
+					}
+					if adminCommands[match[2]] {
+						ch <- prometheus.MustNewConstMetric(
+							globalCommandsDesc, prometheus.CounterValue, floatVal, match[2],
+						)
+					} else if strings.HasPrefix(match[2], "show_") {
+						showCommands += floatVal
+						hasShowCommands = true
+					}
 				}
 			case "handler":
 				ch <- prometheus.MustNewConstMetric(
@@ -138,7 +645,7 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prom
 				)
 			case "innodb_buffer_pool_pages":
 				switch match[2] {
-				case "data", "free", "misc", "old":
+				case "data", "free", "latched", "misc", "old":
 					ch <- prometheus.MustNewConstMetric(
 						globalBufferPoolPagesDesc, prometheus.GaugeValue, floatVal, match[2],
 					)
@@ -147,7 +654,20 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prom
 						globalBufferPoolDirtyPagesDesc, prometheus.GaugeValue, floatVal,
 					)
 				case "total":
-					continue
+					if !*globalStatusBufferPoolPagesTotal {
+						continue
+					}
+					ch <- prometheus.MustNewConstMetric(
+						globalBufferPoolPagesDesc, prometheus.GaugeValue, floatVal, match[2],
+					)
+				case "flushed":
+					// Still exposed as a page change for existing dashboards.
+					ch <- prometheus.MustNewConstMetric(
+						globalBufferPoolPageChangesDesc, prometheus.CounterValue, floatVal, match[2],
+					)
+					ch <- prometheus.MustNewConstMetric(
+						globalBufferPoolPagesFlushedDesc, prometheus.CounterValue, floatVal,
+					)
 				default:
 					ch <- prometheus.MustNewConstMetric(
 						globalBufferPoolPageChangesDesc, prometheus.CounterValue, floatVal, match[2],
@@ -157,6 +677,10 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prom
 				ch <- prometheus.MustNewConstMetric(
 					globalInnoDBRowOpsDesc, prometheus.CounterValue, floatVal, match[2],
 				)
+			case "innodb_pages":
+				ch <- prometheus.MustNewConstMetric(
+					globalInnoDBPagesDesc, prometheus.CounterValue, floatVal, match[2],
+				)
 			case "ssl":
 				continue
 			case "mysqlx":
@@ -169,9 +693,64 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prom
 				continue
 			case "binlog_stmt_cache":
 				continue
+			case "rpl_semi_sync":
+				switch match[2] {
+				case "master_status":
+					ch <- prometheus.MustNewConstMetric(
+						globalSemiSyncMasterStatusDesc, prometheus.GaugeValue, floatVal,
+					)
+				case "master_clients":
+					ch <- prometheus.MustNewConstMetric(
+						globalSemiSyncMasterClientsDesc, prometheus.GaugeValue, floatVal,
+					)
+				case "master_wait_sessions":
+					ch <- prometheus.MustNewConstMetric(
+						globalSemiSyncMasterWaitSessionsDesc, prometheus.GaugeValue, floatVal,
+					)
+				case "master_yes_tx":
+					ch <- prometheus.MustNewConstMetric(
+						globalSemiSyncMasterTransactionsDesc, prometheus.CounterValue, floatVal, "yes",
+					)
+				case "master_no_tx":
+					ch <- prometheus.MustNewConstMetric(
+						globalSemiSyncMasterTransactionsDesc, prometheus.CounterValue, floatVal, "no",
+					)
+				case "slave_status":
+					ch <- prometheus.MustNewConstMetric(
+						globalSemiSyncSlaveStatusDesc, prometheus.GaugeValue, floatVal,
+					)
+				default:
+					ch <- prometheus.MustNewConstMetric(
+						newDesc(globalStatus, key, "Generic metric from SHOW GLOBAL STATUS."),
+						prometheus.UntypedValue,
+						floatVal,
+					)
+				}
+			case "threadpool":
+				switch match[2] {
+				case "threads":
+					ch <- prometheus.MustNewConstMetric(
+						globalThreadpoolThreadsDesc, prometheus.GaugeValue, floatVal, "total",
+					)
+				case "idle_threads":
+					ch <- prometheus.MustNewConstMetric(
+						globalThreadpoolThreadsDesc, prometheus.GaugeValue, floatVal, "idle",
+					)
+				default:
+					ch <- prometheus.MustNewConstMetric(
+						newDesc(globalStatus, key, "Generic metric from SHOW GLOBAL STATUS."),
+						prometheus.UntypedValue,
+						floatVal,
+					)
+				}
 			}
 		}
 	}
+	if hasShowCommands {
+		ch <- prometheus.MustNewConstMetric(
+			globalCommandsDesc, prometheus.CounterValue, showCommands, "show",
+		)
+	}
 	return nil
 }
 
@@ -15,6 +15,8 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -22,87 +24,553 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 func TestScrapeGlobalStatus(t *testing.T) {
+	type metric struct {
+		name   string
+		result MetricResult
+	}
+	testCases := []struct {
+		name     string
+		flags    []string
+		rows     [][2]string
+		expected []metric
+	}{
+		{
+			name: "default",
+			rows: [][2]string{
+				{"Com_alter_db", "1"},
+				{"Com_show_status", "2"},
+				{"Com_select", "3"},
+				{"Connection_errors_internal", "4"},
+				{"Handler_commit", "5"},
+				{"Innodb_buffer_pool_pages_data", "6"},
+				{"Innodb_buffer_pool_pages_flushed", "7"},
+				{"Innodb_buffer_pool_pages_dirty", "7"},
+				{"Innodb_buffer_pool_pages_free", "8"},
+				{"Innodb_buffer_pool_pages_misc", "9"},
+				{"Innodb_buffer_pool_pages_old", "10"},
+				{"Innodb_buffer_pool_pages_total", "11"},
+				{"Innodb_buffer_pool_pages_lru_flushed", "13"},
+				{"Innodb_buffer_pool_pages_made_not_young", "14"},
+				{"Innodb_buffer_pool_pages_made_young", "15"},
+				{"Innodb_rows_read", "8"},
+				{"Performance_schema_users_lost", "9"},
+				{"Slave_running", "OFF"},
+				{"Ssl_version", ""},
+				{"Uptime", "10"},
+				{"validate_password.dictionary_file_words_count", "11"},
+			},
+			expected: []metric{
+				{"mysql_global_status_connection_errors_total", MetricResult{labels: labelMap{"error": "internal"}, value: 4, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_handlers_total", MetricResult{labels: labelMap{"handler": "commit"}, value: 5, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "data"}, value: 6, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_page_changes_total", MetricResult{labels: labelMap{"operation": "flushed"}, value: 7, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_buffer_pool_pages_flushed_total", MetricResult{labels: labelMap{}, value: 7, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_buffer_pool_dirty_pages", MetricResult{labels: labelMap{}, value: 7, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "free"}, value: 8, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "misc"}, value: 9, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "old"}, value: 10, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_page_changes_total", MetricResult{labels: labelMap{"operation": "lru_flushed"}, value: 13, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_buffer_pool_page_changes_total", MetricResult{labels: labelMap{"operation": "made_not_young"}, value: 14, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_buffer_pool_page_changes_total", MetricResult{labels: labelMap{"operation": "made_young"}, value: 15, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_rows_read", MetricResult{labels: labelMap{}, value: 8, metricType: dto.MetricType_UNTYPED}},
+				{"mysql_global_status_slave_running", MetricResult{labels: labelMap{}, value: 0, metricType: dto.MetricType_UNTYPED}},
+				{"mysql_global_status_uptime", MetricResult{labels: labelMap{}, value: 10, metricType: dto.MetricType_UNTYPED}},
+				{"mysql_global_status_validate_password_dictionary_file_words_count", MetricResult{labels: labelMap{}, value: 11, metricType: dto.MetricType_UNTYPED}},
+			},
+		},
+		{
+			name: "threadpool",
+			rows: [][2]string{
+				{"Threadpool_idle_threads", "6"},
+				{"Threadpool_threads", "8"},
+				{"Threadpool_stalls", "3"},
+			},
+			expected: []metric{
+				{"mysql_global_status_threadpool_threads", MetricResult{labels: labelMap{"state": "idle"}, value: 6, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_threadpool_threads", MetricResult{labels: labelMap{"state": "total"}, value: 8, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_threadpool_stalls", MetricResult{labels: labelMap{}, value: 3, metricType: dto.MetricType_UNTYPED}},
+			},
+		},
+		{
+			name: "read ahead",
+			rows: [][2]string{
+				{"Innodb_buffer_pool_read_ahead_rnd", "1"},
+				{"Innodb_buffer_pool_read_ahead", "2"},
+				{"Innodb_buffer_pool_read_ahead_evicted", "3"},
+			},
+			expected: []metric{
+				{"mysql_global_status_innodb_buffer_pool_read_ahead_total", MetricResult{labels: labelMap{"type": "random"}, value: 1, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_buffer_pool_read_ahead_total", MetricResult{labels: labelMap{"type": "linear"}, value: 2, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_buffer_pool_read_ahead_total", MetricResult{labels: labelMap{"type": "evicted"}, value: 3, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "innodb pages",
+			rows: [][2]string{
+				{"Innodb_pages_created", "1"},
+				{"Innodb_pages_read", "2"},
+				{"Innodb_pages_written", "3"},
+			},
+			expected: []metric{
+				{"mysql_global_status_innodb_pages_total", MetricResult{labels: labelMap{"operation": "created"}, value: 1, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_pages_total", MetricResult{labels: labelMap{"operation": "read"}, value: 2, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_pages_total", MetricResult{labels: labelMap{"operation": "written"}, value: 3, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "connection control",
+			rows: [][2]string{
+				{"Connection_control_delay_generated", "12"},
+			},
+			expected: []metric{
+				{"mysql_global_status_connection_control_delay_generated_total", MetricResult{labels: labelMap{}, value: 12, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "buffer pool write pressure",
+			rows: [][2]string{
+				{"Innodb_buffer_pool_pages_flushed", "40"},
+				{"Innodb_buffer_pool_wait_free", "3"},
+			},
+			expected: []metric{
+				{"mysql_global_status_buffer_pool_page_changes_total", MetricResult{labels: labelMap{"operation": "flushed"}, value: 40, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_buffer_pool_pages_flushed_total", MetricResult{labels: labelMap{}, value: 40, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_buffer_pool_wait_free_total", MetricResult{labels: labelMap{}, value: 3, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "gtid safety",
+			rows: [][2]string{
+				{"Ongoing_anonymous_transaction_count", "2"},
+				{"Slave_last_heartbeat", "2021-10-01 12:00:00"},
+			},
+			expected: []metric{
+				{"mysql_global_status_ongoing_anonymous_transaction_count", MetricResult{labels: labelMap{}, value: 2, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_slave_last_heartbeat_timestamp_seconds", MetricResult{labels: labelMap{}, value: 1633089600, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			name:  "binlog cache",
+			flags: []string{"--collect.global_status.binlog_cache"},
+			rows: [][2]string{
+				{"Binlog_cache_disk_use", "12"},
+				{"Binlog_cache_use", "3400"},
+				{"Binlog_stmt_cache_disk_use", "1"},
+				{"Binlog_stmt_cache_use", "56"},
+			},
+			expected: []metric{
+				{"mysql_global_status_binlog_cache_total", MetricResult{labels: labelMap{"type": "transactional", "location": "disk"}, value: 12, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_binlog_cache_total", MetricResult{labels: labelMap{"type": "transactional", "location": "all"}, value: 3400, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_binlog_cache_total", MetricResult{labels: labelMap{"type": "non_transactional", "location": "disk"}, value: 1, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_binlog_cache_total", MetricResult{labels: labelMap{"type": "non_transactional", "location": "all"}, value: 56, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "slow queries",
+			rows: [][2]string{
+				{"Slow_queries", "42"},
+			},
+			expected: []metric{
+				{"mysql_global_status_slow_queries_total", MetricResult{labels: labelMap{}, value: 42, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "open files",
+			rows: [][2]string{
+				{"Innodb_num_open_files", "38"},
+				{"Open_files", "12"},
+				{"Open_streams", "0"},
+			},
+			expected: []metric{
+				{"mysql_global_status_innodb_num_open_files", MetricResult{labels: labelMap{}, value: 38, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_open_files", MetricResult{labels: labelMap{}, value: 12, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_open_streams", MetricResult{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			name: "prepared statements",
+			rows: [][2]string{
+				{"Com_stmt_close", "90"},
+				{"Com_stmt_execute", "1200"},
+				{"Com_stmt_fetch", "3"},
+				{"Com_stmt_prepare", "100"},
+				{"Prepared_stmt_count", "10"},
+			},
+			expected: []metric{
+				{"mysql_global_status_com_stmt_total", MetricResult{labels: labelMap{"operation": "close"}, value: 90, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_com_stmt_total", MetricResult{labels: labelMap{"operation": "execute"}, value: 1200, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_com_stmt_total", MetricResult{labels: labelMap{"operation": "prepare"}, value: 100, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_prepared_statements", MetricResult{labels: labelMap{}, value: 10, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			name: "buffer pool hit ratio",
+			rows: [][2]string{
+				{"Innodb_buffer_pool_read_requests", "98000"},
+				{"Innodb_buffer_pool_reads", "2000"},
+			},
+			expected: []metric{
+				{"mysql_global_status_innodb_buffer_pool_read_requests_total", MetricResult{labels: labelMap{}, value: 98000, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_buffer_pool_reads_total", MetricResult{labels: labelMap{}, value: 2000, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "acl cache",
+			rows: [][2]string{
+				{"Acl_cache_items_count", "1342"},
+			},
+			expected: []metric{
+				{"mysql_global_status_acl_cache_items_count", MetricResult{labels: labelMap{}, value: 1342, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			name: "table locks",
+			rows: [][2]string{
+				{"Table_locks_immediate", "9820"},
+				{"Table_locks_waited", "12"},
+			},
+			expected: []metric{
+				{"mysql_global_status_table_locks_total", MetricResult{labels: labelMap{"status": "immediate"}, value: 9820, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_table_locks_total", MetricResult{labels: labelMap{"status": "waited"}, value: 12, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name:  "admin commands",
+			flags: []string{"--collect.global_status.admin_commands"},
+			rows: [][2]string{
+				{"Com_admin_commands", "40"},
+				{"Com_change_db", "12"},
+				{"Com_select", "900"},
+				{"Com_set_option", "300"},
+				{"Com_show_status", "25"},
+				{"Com_show_variables", "5"},
+			},
+			expected: []metric{
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "admin_commands"}, value: 40, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "change_db"}, value: 12, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "set_option"}, value: 300, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "show"}, value: 30, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			// Percona Server status variables.
+			name: "percona locks",
+			rows: [][2]string{
+				{"Innodb_row_lock_waits", "41"},
+				{"Innodb_deadlocks", "3"},
+				{"Innodb_lock_timeouts", "7"},
+			},
+			expected: []metric{
+				{"mysql_global_status_innodb_row_lock_waits", MetricResult{labels: labelMap{}, value: 41, metricType: dto.MetricType_UNTYPED}},
+				{"mysql_global_status_innodb_deadlocks_total", MetricResult{labels: labelMap{}, value: 3, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_innodb_lock_timeouts_total", MetricResult{labels: labelMap{}, value: 7, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name:  "xa",
+			flags: []string{"--collect.global_status.xa"},
+			rows: [][2]string{
+				{"Com_xa_commit", "10"},
+				{"Com_xa_end", "12"},
+				{"Com_xa_prepare", "11"},
+				{"Com_xa_recover", "1"},
+				{"Com_xa_rollback", "1"},
+				{"Com_xa_start", "12"},
+			},
+			expected: []metric{
+				{"mysql_global_status_com_xa_total", MetricResult{labels: labelMap{"operation": "commit"}, value: 10, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_com_xa_total", MetricResult{labels: labelMap{"operation": "end"}, value: 12, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_com_xa_total", MetricResult{labels: labelMap{"operation": "prepare"}, value: 11, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_com_xa_total", MetricResult{labels: labelMap{"operation": "recover"}, value: 1, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_com_xa_total", MetricResult{labels: labelMap{"operation": "rollback"}, value: 1, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_com_xa_total", MetricResult{labels: labelMap{"operation": "start"}, value: 12, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name:  "buffer pool pages total",
+			flags: []string{"--collect.global_status.buffer_pool_pages_total"},
+			rows: [][2]string{
+				{"Innodb_buffer_pool_pages_free", "1024"},
+				{"Innodb_buffer_pool_pages_total", "8192"},
+			},
+			expected: []metric{
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "free"}, value: 1024, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "total"}, value: 8192, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			name:  "ddl",
+			flags: []string{"--collect.global_status.ddl"},
+			rows: [][2]string{
+				{"Com_alter_table", "12"},
+				{"Com_create_index", "3"},
+				{"Com_drop_index", "2"},
+				{"Com_stmt_reprepare", "57"},
+				{"Com_alter_user", "1"},
+			},
+			expected: []metric{
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "alter_table"}, value: 12, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "create_index"}, value: 3, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "drop_index"}, value: 2, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_commands_total", MetricResult{labels: labelMap{"command": "stmt_reprepare"}, value: 57, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "connections",
+			rows: [][2]string{
+				{"Connections", "1500"},
+				{"Threads_created", "42"},
+			},
+			expected: []metric{
+				{"mysql_global_status_connections_total", MetricResult{labels: labelMap{}, value: 1500, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_threads_created_total", MetricResult{labels: labelMap{}, value: 42, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "questions and queries",
+			rows: [][2]string{
+				{"Questions", "1200"},
+				{"Queries", "1350"},
+			},
+			expected: []metric{
+				{"mysql_global_status_questions_total", MetricResult{labels: labelMap{}, value: 1200, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_queries_total", MetricResult{labels: labelMap{}, value: 1350, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "buffer pool page states",
+			rows: [][2]string{
+				{"Innodb_buffer_pool_pages_data", "7000"},
+				{"Innodb_buffer_pool_pages_free", "1024"},
+				{"Innodb_buffer_pool_pages_latched", "3"},
+				{"Innodb_buffer_pool_pages_misc", "165"},
+				{"Innodb_buffer_pool_pages_old", "2580"},
+			},
+			expected: []metric{
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "data"}, value: 7000, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "free"}, value: 1024, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "latched"}, value: 3, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "misc"}, value: 165, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_buffer_pool_pages", MetricResult{labels: labelMap{"state": "old"}, value: 2580, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			name: "semi-sync master",
+			rows: [][2]string{
+				{"Rpl_semi_sync_master_clients", "2"},
+				{"Rpl_semi_sync_master_no_tx", "3"},
+				{"Rpl_semi_sync_master_status", "OFF"},
+				{"Rpl_semi_sync_master_wait_sessions", "1"},
+				{"Rpl_semi_sync_master_yes_tx", "1500"},
+			},
+			expected: []metric{
+				{"mysql_global_status_rpl_semi_sync_master_clients", MetricResult{labels: labelMap{}, value: 2, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_rpl_semi_sync_master_transactions_total", MetricResult{labels: labelMap{"acknowledged": "no"}, value: 3, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_rpl_semi_sync_master_status", MetricResult{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_rpl_semi_sync_master_wait_sessions", MetricResult{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_rpl_semi_sync_master_transactions_total", MetricResult{labels: labelMap{"acknowledged": "yes"}, value: 1500, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+		{
+			name: "semi-sync slave",
+			rows: [][2]string{
+				{"Rpl_semi_sync_master_net_avg_wait_time", "250"},
+				{"Rpl_semi_sync_slave_status", "ON"},
+			},
+			expected: []metric{
+				{"mysql_global_status_rpl_semi_sync_master_net_avg_wait_time", MetricResult{labels: labelMap{}, value: 250, metricType: dto.MetricType_UNTYPED}},
+				{"mysql_global_status_rpl_semi_sync_slave_status", MetricResult{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			// Percona Server status variables.
+			name: "percona purge",
+			rows: [][2]string{
+				{"Innodb_purge_trx_id", "43210"},
+				{"Innodb_purge_trx_id_age", "120"},
+				{"Innodb_purge_view_trx_id_age", "15"},
+			},
+			expected: []metric{
+				{"mysql_global_status_innodb_purge_trx_id", MetricResult{labels: labelMap{}, value: 43210, metricType: dto.MetricType_UNTYPED}},
+				{"mysql_global_status_innodb_purge_trx_id_age", MetricResult{labels: labelMap{}, value: 120, metricType: dto.MetricType_GAUGE}},
+				{"mysql_global_status_innodb_purge_view_trx_id_age", MetricResult{labels: labelMap{}, value: 15, metricType: dto.MetricType_GAUGE}},
+			},
+		},
+		{
+			name: "buffer pool made young",
+			rows: [][2]string{
+				{"Innodb_buffer_pool_pages_made_not_young", "42"},
+				{"Innodb_buffer_pool_pages_made_young", "7"},
+			},
+			expected: []metric{
+				{"mysql_global_status_buffer_pool_page_changes_total", MetricResult{labels: labelMap{"operation": "made_not_young"}, value: 42, metricType: dto.MetricType_COUNTER}},
+				{"mysql_global_status_buffer_pool_page_changes_total", MetricResult{labels: labelMap{"operation": "made_young"}, value: 7, metricType: dto.MetricType_COUNTER}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := kingpin.CommandLine.Parse(tc.flags); err != nil {
+				t.Fatal(err)
+			}
+			defer kingpin.CommandLine.Parse([]string{})
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("error opening a stub database connection: %s", err)
+			}
+			defer db.Close()
+
+			rows := sqlmock.NewRows([]string{"Variable_name", "Value"})
+			for _, row := range tc.rows {
+				rows.AddRow(row[0], row[1])
+			}
+			mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(rows)
+
+			ch := make(chan prometheus.Metric)
+			go func() {
+				if err := (ScrapeGlobalStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+					t.Errorf("error calling function on test: %s", err)
+				}
+				close(ch)
+			}()
+
+			convey.Convey("Metrics comparison", t, func() {
+				for _, expect := range tc.expected {
+					m, ok := <-ch
+					convey.So(ok, convey.ShouldBeTrue)
+					convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+					convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+				}
+				// No other metric or generic duplicate is emitted.
+				_, ok := <-ch
+				convey.So(ok, convey.ShouldBeFalse)
+			})
+
+			// Ensure all SQL queries were executed
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled exceptions: %s", err)
+			}
+		})
+	}
+}
+
+func TestScrapeGlobalStatusEmitDeltas(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.emit-deltas=Com_select,Questions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+	defer func() { globalStatusDeltas = &statusDeltas{previous: map[string]float64{}} }()
+
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error opening a stub database connection: %s", err)
 	}
 	defer db.Close()
 
+	// Questions went backwards as the server restarted in between.
 	columns := []string{"Variable_name", "Value"}
-	rows := sqlmock.NewRows(columns).
-		AddRow("Com_alter_db", "1").
-		AddRow("Com_show_status", "2").
-		AddRow("Com_select", "3").
-		AddRow("Connection_errors_internal", "4").
-		AddRow("Handler_commit", "5").
-		AddRow("Innodb_buffer_pool_pages_data", "6").
-		AddRow("Innodb_buffer_pool_pages_flushed", "7").
-		AddRow("Innodb_buffer_pool_pages_dirty", "7").
-		AddRow("Innodb_buffer_pool_pages_free", "8").
-		AddRow("Innodb_buffer_pool_pages_misc", "9").
-		AddRow("Innodb_buffer_pool_pages_old", "10").
-		AddRow("Innodb_buffer_pool_pages_total", "11").
-		AddRow("Innodb_buffer_pool_pages_lru_flushed", "13").
-		AddRow("Innodb_buffer_pool_pages_made_not_young", "14").
-		AddRow("Innodb_buffer_pool_pages_made_young", "15").
-		AddRow("Innodb_rows_read", "8").
-		AddRow("Performance_schema_users_lost", "9").
-		AddRow("Slave_running", "OFF").
-		AddRow("Ssl_version", "").
-		AddRow("Uptime", "10").
-		AddRow("validate_password.dictionary_file_words_count", "11")
-		// AddRow("wsrep_cluster_status", "Primary").
-		// AddRow("wsrep_local_state_uuid", "6c06e583-686f-11e6-b9e3-8336ad58138c").
-		// AddRow("wsrep_cluster_state_uuid", "6c06e583-686f-11e6-b9e3-8336ad58138c").
-		// AddRow("wsrep_provider_version", "3.16(r5c765eb)").
-		// AddRow("wsrep_evs_repl_latency", "0.000227664/0.00034135/0.000544298/6.03708e-05/212")
-	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(rows)
-
-	ch := make(chan prometheus.Metric)
-	go func() {
-		if err = (ScrapeGlobalStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
-			t.Errorf("error calling function on test: %s", err)
+	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("Com_select", "100").AddRow("Questions", "200"),
+	)
+	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("Com_select", "150").AddRow("Questions", "30"),
+	)
+
+	scrapeDeltas := func() map[string]float64 {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			if err := (ScrapeGlobalStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+				t.Errorf("error calling function on test: %s", err)
+			}
+			close(ch)
+		}()
+		deltas := map[string]float64{}
+		for m := range ch {
+			if desc := m.Desc().String(); strings.Contains(desc, "_delta\"") {
+				got := readMetric(m)
+				if got.metricType != dto.MetricType_GAUGE {
+					t.Errorf("expected a gauge, got %s", got.metricType)
+				}
+				deltas[desc[strings.Index(desc, "mysql_"):strings.Index(desc, "_delta")]] = got.value
+			}
 		}
-		close(ch)
-	}()
-
-	counterExpected := []MetricResult{
-		{labels: labelMap{"command": "alter_db"}, value: 1, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"command": "show_status"}, value: 2, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"command": "select"}, value: 3, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"error": "internal"}, value: 4, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"handler": "commit"}, value: 5, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"state": "data"}, value: 6, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{"operation": "flushed"}, value: 7, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{}, value: 7, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{"state": "free"}, value: 8, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{"state": "misc"}, value: 9, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{"state": "old"}, value: 10, metricType: dto.MetricType_GAUGE},
-		//{labels: labelMap{"state": "total_pages"}, value: 11, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{"operation": "lru_flushed"}, value: 13, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"operation": "made_not_young"}, value: 14, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"operation": "made_young"}, value: 15, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"operation": "read"}, value: 8, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{"instrumentation": "users_lost"}, value: 9, metricType: dto.MetricType_COUNTER},
-		{labels: labelMap{}, value: 0, metricType: dto.MetricType_UNTYPED},
-		{labels: labelMap{}, value: 10, metricType: dto.MetricType_UNTYPED},
-		{labels: labelMap{}, value: 11, metricType: dto.MetricType_UNTYPED},
-		{labels: labelMap{}, value: 1, metricType: dto.MetricType_UNTYPED},
-		// {labels: labelMap{"wsrep_local_state_uuid": "6c06e583-686f-11e6-b9e3-8336ad58138c", "wsrep_cluster_state_uuid": "6c06e583-686f-11e6-b9e3-8336ad58138c", "wsrep_provider_version": "3.16(r5c765eb)"}, value: 1, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{}, value: 0.000227664, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{}, value: 0.00034135, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{}, value: 0.000544298, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{}, value: 6.03708e-05, metricType: dto.MetricType_GAUGE},
-		{labels: labelMap{}, value: 212, metricType: dto.MetricType_GAUGE},
+		return deltas
 	}
-	convey.Convey("Metrics comparison", t, func() {
-		for _, expect := range counterExpected {
-			got := readMetric(<-ch)
-			convey.So(got, convey.ShouldResemble, expect)
+
+	convey.Convey("Deltas", t, func() {
+		convey.So(scrapeDeltas(), convey.ShouldBeEmpty)
+		convey.So(scrapeDeltas(), convey.ShouldResemble, map[string]float64{
+			"mysql_global_status_com_select": 50,
+			"mysql_global_status_questions":  30,
+		})
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeGlobalStatusServeStale(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.global_status.serve-stale=1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+	defer func() { globalStatusLastGood = &staleStatus{} }()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	timeout := errors.New("context deadline exceeded")
+	columns := []string{"Variable_name", "Value"}
+	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnError(timeout)
+	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(sqlmock.NewRows(columns).AddRow("Uptime", "10"))
+	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnError(timeout)
+
+	scrape := func() ([]MetricResult, error) {
+		ch := make(chan prometheus.Metric)
+		var err error
+		go func() {
+			err = (ScrapeGlobalStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger())
+			close(ch)
+		}()
+		var got []MetricResult
+		for m := range ch {
+			got = append(got, readMetric(m))
 		}
+		return got, err
+	}
+
+	convey.Convey("Serve stale", t, func() {
+		// Nothing to serve before a successful scrape.
+		got, err := scrape()
+		convey.So(err, convey.ShouldEqual, timeout)
+		convey.So(got, convey.ShouldBeEmpty)
+
+		got, err = scrape()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{}, value: 10, metricType: dto.MetricType_UNTYPED},
+			{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE},
+		})
+
+		got, err = scrape()
+		convey.So(err, convey.ShouldEqual, timeout)
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{}, value: 10, metricType: dto.MetricType_UNTYPED},
+			{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		})
 	})
 
 	// Ensure all SQL queries were executed
@@ -0,0 +1,118 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `SHOW GLOBAL STATUS` and `SHOW GLOBAL VARIABLES` in one round trip.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/go-kit/log"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const globalStatusVariablesQuery = globalStatusQuery + "; " + globalVariablesQuery
+
+// Tunable flags.
+var (
+	combineStatusVariables = kingpin.Flag(
+		"collect.combine-status-variables",
+		"Collect global_status and global_variables with a single multi-statement query, requires multiStatements=true in the DSN",
+	).Default("false").Bool()
+)
+
+// ScrapeGlobalStatusVariables collects from `SHOW GLOBAL STATUS` and
+// `SHOW GLOBAL VARIABLES` with a single multi-statement query. It replaces
+// ScrapeGlobalStatus and ScrapeGlobalVariables when both are enabled.
+type ScrapeGlobalStatusVariables struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeGlobalStatusVariables) Name() string {
+	return "global_status_variables"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeGlobalStatusVariables) Help() string {
+	return "Collect from SHOW GLOBAL STATUS and SHOW GLOBAL VARIABLES in one query"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeGlobalStatusVariables) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeGlobalStatusVariables) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var rows *sql.Rows
+	err := serveGlobalStatus(ch, logger, func(ch chan<- prometheus.Metric) error {
+		var err error
+		if rows, err = db.QueryContext(ctx, globalStatusVariablesQuery); err != nil {
+			return err
+		}
+		return scrapeGlobalStatusRows(rows, ch)
+	})
+	if rows != nil {
+		defer rows.Close()
+	}
+	if err != nil {
+		return err
+	}
+	if !rows.NextResultSet() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return errors.New("missing SHOW GLOBAL VARIABLES result")
+	}
+	return scrapeGlobalVariablesRows(rows, ch)
+}
+
+// combineGlobalStatusVariables replaces ScrapeGlobalStatus and
+// ScrapeGlobalVariables by ScrapeGlobalStatusVariables if both are present.
+func combineGlobalStatusVariables(scrapers []Scraper) []Scraper {
+	var hasStatus, hasVariables bool
+	for _, scraper := range scrapers {
+		switch scraper.(type) {
+		case ScrapeGlobalStatus:
+			hasStatus = true
+		case ScrapeGlobalVariables:
+			hasVariables = true
+		}
+	}
+	if !hasStatus || !hasVariables {
+		return scrapers
+	}
+
+	combined := []Scraper{ScrapeGlobalStatusVariables{}}
+	for _, scraper := range scrapers {
+		switch scraper.(type) {
+		case ScrapeGlobalStatus, ScrapeGlobalVariables:
+		default:
+			combined = append(combined, scraper)
+		}
+	}
+	return combined
+}
+
+// multiStatementsEnabled tells whether the DSN allows several statements per query.
+func multiStatementsEnabled(dsn string) bool {
+	cfg, err := gomysql.ParseDSN(dsn)
+	return err == nil && cfg.MultiStatements
+}
+
+// check interface
+var _ Scraper = ScrapeGlobalStatusVariables{}
@@ -0,0 +1,153 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestScrapeGlobalStatusVariables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Variable_name", "Value"}
+	statusRows := sqlmock.NewRows(columns).
+		AddRow("Threads_connected", "7")
+	variablesRows := sqlmock.NewRows(columns).
+		AddRow("max_connections", "151")
+	mock.ExpectQuery(sanitizeQuery(globalStatusVariablesQuery)).WillReturnRows(statusRows, variablesRows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeGlobalStatusVariables{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_global_status_threads_connected", MetricResult{labels: labelMap{}, value: 7, metricType: dto.MetricType_UNTYPED}},
+		{"mysql_global_variables_max_connections", MetricResult{labels: labelMap{}, value: 151, metricType: dto.MetricType_GAUGE}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeGlobalStatusVariablesServeStale(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.global_status.serve-stale=1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+	defer func() { globalStatusLastGood = &staleStatus{} }()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	timeout := errors.New("context deadline exceeded")
+	columns := []string{"Variable_name", "Value"}
+	mock.ExpectQuery(sanitizeQuery(globalStatusVariablesQuery)).WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("Uptime", "10"),
+		sqlmock.NewRows(columns).AddRow("max_connections", "151"),
+	)
+	mock.ExpectQuery(sanitizeQuery(globalStatusVariablesQuery)).WillReturnError(timeout)
+
+	scrape := func() ([]MetricResult, error) {
+		ch := make(chan prometheus.Metric)
+		var err error
+		go func() {
+			err = (ScrapeGlobalStatusVariables{}).Scrape(context.Background(), db, ch, log.NewNopLogger())
+			close(ch)
+		}()
+		var got []MetricResult
+		for m := range ch {
+			got = append(got, readMetric(m))
+		}
+		return got, err
+	}
+
+	convey.Convey("Serve stale global status", t, func() {
+		got, err := scrape()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{}, value: 10, metricType: dto.MetricType_UNTYPED},
+			{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE},
+			{labels: labelMap{}, value: 151, metricType: dto.MetricType_GAUGE},
+		})
+
+		// Only the global status is served stale.
+		got, err = scrape()
+		convey.So(err, convey.ShouldEqual, timeout)
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{}, value: 10, metricType: dto.MetricType_UNTYPED},
+			{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		})
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestCombineGlobalStatusVariables(t *testing.T) {
+	convey.Convey("Combine global status and variables", t, func() {
+		convey.Convey("Both enabled", func() {
+			scrapers := combineGlobalStatusVariables([]Scraper{ScrapeGlobalStatus{}, ScrapeSlaveStatus{}, ScrapeGlobalVariables{}})
+			convey.So(scrapers, convey.ShouldResemble, []Scraper{ScrapeGlobalStatusVariables{}, ScrapeSlaveStatus{}})
+		})
+		convey.Convey("Only one enabled", func() {
+			scrapers := combineGlobalStatusVariables([]Scraper{ScrapeGlobalStatus{}, ScrapeSlaveStatus{}})
+			convey.So(scrapers, convey.ShouldResemble, []Scraper{ScrapeGlobalStatus{}, ScrapeSlaveStatus{}})
+		})
+	})
+}
+
+func TestMultiStatementsEnabled(t *testing.T) {
+	convey.Convey("multiStatements DSN parameter", t, func() {
+		convey.So(multiStatementsEnabled("user@tcp(db:3306)/?multiStatements=true"), convey.ShouldBeTrue)
+		convey.So(multiStatementsEnabled("user@tcp(db:3306)/"), convey.ShouldBeFalse)
+		convey.So(multiStatementsEnabled("not a dsn"), convey.ShouldBeFalse)
+	})
+}
@@ -0,0 +1,202 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `SHOW GLOBAL VARIABLES`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	// Metric subsystem
+	globalVariables = "global_variables"
+	// Metric SQL Queries.
+	globalVariablesQuery = `SHOW GLOBAL VARIABLES`
+)
+
+// Tunable flags.
+var (
+	globalVariablesInclude = kingpin.Flag(
+		"collect.global_variables.include",
+		"Regexp of variable names to collect from SHOW GLOBAL VARIABLES, all if empty.",
+	).Default("").Regexp()
+	globalVariablesExclude = kingpin.Flag(
+		"collect.global_variables.exclude",
+		"Regexp of variable names to skip from SHOW GLOBAL VARIABLES, none if empty.",
+	).Default("").Regexp()
+	globalVariablesStringAsLabel = kingpin.Flag(
+		"collect.global_variables.string-as-label",
+		"Collect non-numeric variables as info metrics with the value as a label.",
+	).Default("false").Bool()
+)
+
+// Metric descriptors.
+var (
+	globalVariablesGeneralLogDesc = newNamespacedDesc(
+		globalVariables, "general_log",
+		"Whether the general query log is enabled (1 for ON, 0 for OFF).",
+		nil, nil,
+	)
+	globalVariablesSlowQueryLogDesc = newNamespacedDesc(
+		globalVariables, "slow_query_log",
+		"Whether the slow query log is enabled (1 for ON, 0 for OFF).",
+		nil, nil,
+	)
+	globalVariablesLogOutputDesc = newNamespacedDesc(
+		globalVariables, "log_output",
+		"Destinations of the general and slow query logs (1 if the destination is selected).",
+		[]string{"output"}, nil,
+	)
+)
+
+// Maximum length of a non-numeric value exposed as a label, e.g. of sql_mode or init_connect.
+const globalVariablesLabelMaxLength = 256
+
+// globalVariablesInfoDescs keeps the descriptors of the _info metrics by variable name.
+var globalVariablesInfoDescs = struct {
+	sync.Mutex
+	descs map[string]*prometheus.Desc
+}{descs: map[string]*prometheus.Desc{}}
+
+func globalVariablesInfoDesc(key string) *prometheus.Desc {
+	globalVariablesInfoDescs.Lock()
+	defer globalVariablesInfoDescs.Unlock()
+	desc, ok := globalVariablesInfoDescs.descs[key]
+	if !ok {
+		desc = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, globalVariables, key+"_info"),
+			"Value of a non-numeric variable from SHOW GLOBAL VARIABLES.",
+			[]string{"value"}, nil,
+		)
+		globalVariablesInfoDescs.descs[key] = desc
+	}
+	return desc
+}
+
+// logOutputValues are the possible destinations of the log_output variable.
+var logOutputValues = []string{"FILE", "TABLE", "NONE"}
+
+// globalVariableSelected tells whether a variable passes the include and exclude filters.
+func globalVariableSelected(name string) bool {
+	if re := *globalVariablesInclude; re != nil && re.String() != "" && !re.MatchString(name) {
+		return false
+	}
+	if re := *globalVariablesExclude; re != nil && re.String() != "" && re.MatchString(name) {
+		return false
+	}
+	return true
+}
+
+// ScrapeGlobalVariables collects from `SHOW GLOBAL VARIABLES`.
+type ScrapeGlobalVariables struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeGlobalVariables) Name() string {
+	return globalVariables
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeGlobalVariables) Help() string {
+	return "Collect from SHOW GLOBAL VARIABLES"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeGlobalVariables) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeGlobalVariables) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	globalVariablesRows, err := db.QueryContext(ctx, globalVariablesQuery)
+	if err != nil {
+		return err
+	}
+	defer globalVariablesRows.Close()
+	return scrapeGlobalVariablesRows(globalVariablesRows, ch)
+}
+
+// scrapeGlobalVariablesRows sends the metrics of the `SHOW GLOBAL VARIABLES` result.
+func scrapeGlobalVariablesRows(globalVariablesRows *sql.Rows, ch chan<- prometheus.Metric) error {
+	var key string
+	var val sql.RawBytes
+
+	for globalVariablesRows.Next() {
+		if err := globalVariablesRows.Scan(&key, &val); err != nil {
+			return err
+		}
+
+		key = validPrometheusName(key)
+		if !globalVariableSelected(key) {
+			continue
+		}
+		switch key {
+		case "log_output":
+			// log_output is a comma separated set, e.g. "FILE,TABLE".
+			selected := map[string]bool{}
+			for _, output := range strings.Split(strings.ToUpper(string(val)), ",") {
+				selected[strings.TrimSpace(output)] = true
+			}
+			for _, output := range logOutputValues {
+				value := 0.0
+				if selected[output] {
+					value = 1
+				}
+				ch <- prometheus.MustNewConstMetric(
+					globalVariablesLogOutputDesc, prometheus.GaugeValue, value, strings.ToLower(output),
+				)
+			}
+			continue
+		}
+
+		floatVal, ok := parseStatus(val)
+		if !ok {
+			// Unparsable values are skipped unless asked to be collected as labels.
+			if *globalVariablesStringAsLabel {
+				ch <- prometheus.MustNewConstMetric(
+					globalVariablesInfoDesc(key),
+					prometheus.GaugeValue, 1, labelValue(string(val), globalVariablesLabelMaxLength),
+				)
+			}
+			continue
+		}
+		switch key {
+		case "general_log":
+			ch <- prometheus.MustNewConstMetric(
+				globalVariablesGeneralLogDesc, prometheus.GaugeValue, floatVal,
+			)
+		case "slow_query_log":
+			ch <- prometheus.MustNewConstMetric(
+				globalVariablesSlowQueryLogDesc, prometheus.GaugeValue, floatVal,
+			)
+		default:
+			ch <- prometheus.MustNewConstMetric(
+				newDesc(globalVariables, key, "Generic gauge metric from SHOW GLOBAL VARIABLES."),
+				prometheus.GaugeValue,
+				floatVal,
+			)
+		}
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeGlobalVariables{}
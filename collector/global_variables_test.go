@@ -0,0 +1,185 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestScrapeGlobalVariables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Variable_name", "Value"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("general_log", "ON").
+		AddRow("general_log_file", "/var/lib/mysql/host.log").
+		AddRow("log_output", "FILE,TABLE").
+		AddRow("max_connections", "151").
+		AddRow("slow_query_log", "OFF").
+		AddRow("sql_mode", "STRICT_TRANS_TABLES")
+	mock.ExpectQuery(sanitizeQuery(globalVariablesQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeGlobalVariables{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"output": "file"}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"output": "table"}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"output": "none"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 151, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeGlobalVariablesFiltered(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{
+		"--collect.global_variables.include", "^(max_|sql_mode|version)",
+		"--collect.global_variables.exclude", "^max_allowed_packet$",
+		"--collect.global_variables.string-as-label",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Variable_name", "Value"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("general_log", "ON").
+		AddRow("max_allowed_packet", "67108864").
+		AddRow("max_connections", "151").
+		AddRow("sql_mode", "STRICT_TRANS_TABLES").
+		AddRow("version", "8.0.26")
+	mock.ExpectQuery(sanitizeQuery(globalVariablesQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeGlobalVariables{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_global_variables_max_connections", MetricResult{labels: labelMap{}, value: 151, metricType: dto.MetricType_GAUGE}},
+		{"mysql_global_variables_sql_mode_info", MetricResult{labels: labelMap{"value": "STRICT_TRANS_TABLES"}, value: 1, metricType: dto.MetricType_GAUGE}},
+		{"mysql_global_variables_version_info", MetricResult{labels: labelMap{"value": "8.0.26"}, value: 1, metricType: dto.MetricType_GAUGE}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeGlobalVariablesStringLabelValues(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.global_variables.string-as-label"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Variable_name", "Value"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("character_sets_dir", "/usr/share/caf\xe9/charsets/").
+		AddRow("init_connect", strings.Repeat("x", 300))
+	mock.ExpectQuery(sanitizeQuery(globalVariablesQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeGlobalVariables{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	// Invalid UTF-8 is replaced and long values are truncated.
+	metricExpected := []struct {
+		key    string
+		result MetricResult
+	}{
+		{"character_sets_dir", MetricResult{labels: labelMap{"value": "/usr/share/caf�/charsets/"}, value: 1, metricType: dto.MetricType_GAUGE}},
+		{"init_connect", MetricResult{labels: labelMap{"value": strings.Repeat("x", globalVariablesLabelMaxLength)}, value: 1, metricType: dto.MetricType_GAUGE}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			// The descriptor of the variable is reused.
+			convey.So(m.Desc(), convey.ShouldEqual, globalVariablesInfoDesc(expect.key))
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
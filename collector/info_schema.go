@@ -13,5 +13,25 @@
 
 package collector
 
+import "strings"
+
 // Subsystem.
 const informationSchema = "info_schema"
+
+// schemaFilter returns an SQL condition restricting column to the comma separated
+// list of databases along with its arguments. The special list "*" matches every
+// database except the system schemas.
+func schemaFilter(column string, databases string) (string, []interface{}) {
+	if databases == "*" {
+		return column + " NOT IN ('mysql', 'performance_schema', 'information_schema', 'sys')", nil
+	}
+	var (
+		placeholders []string
+		args         []interface{}
+	)
+	for _, database := range strings.Split(databases, ",") {
+		placeholders = append(placeholders, "?")
+		args = append(args, strings.TrimSpace(database))
+	}
+	return column + " IN (" + strings.Join(placeholders, ", ") + ")", args
+}
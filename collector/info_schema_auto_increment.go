@@ -39,13 +39,13 @@ const infoSchemaAutoIncrementQuery = `
 
 // Metric descriptors.
 var (
-	globalInfoSchemaAutoIncrementDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "auto_increment_column"),
+	globalInfoSchemaAutoIncrementDesc = newNamespacedDesc(
+		informationSchema, "auto_increment_column",
 		"The current value of an auto_increment column from information_schema.",
 		[]string{"schema", "table", "column"}, nil,
 	)
-	globalInfoSchemaAutoIncrementMaxDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "auto_increment_column_max"),
+	globalInfoSchemaAutoIncrementMaxDesc = newNamespacedDesc(
+		informationSchema, "auto_increment_column_max",
 		"The max value of an auto_increment column from information_schema.",
 		[]string{"schema", "table", "column"}, nil,
 	)
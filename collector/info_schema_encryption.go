@@ -0,0 +1,129 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape the tablespace encryption from `information_schema.INNODB_TABLESPACES`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// File-per-table tablespaces are named schema/table, the schema is empty
+// for the system, general and undo tablespaces.
+const infoSchemaEncryptionQuery = `
+	SELECT
+	    IF(NAME LIKE '%/%', SUBSTRING_INDEX(NAME, '/', 1), '') AS SCHEMA_NAME,
+	    ENCRYPTION,
+	    COUNT(*)
+	  FROM information_schema.INNODB_TABLESPACES
+	  GROUP BY SCHEMA_NAME, ENCRYPTION
+	`
+
+// Tunable flags.
+var (
+	encryptionBySchema = kingpin.Flag(
+		"collect.info_schema.encryption.by_schema",
+		"Also collect the number of encrypted and unencrypted tablespaces of each schema",
+	).Default("false").Bool()
+)
+
+// Metric descriptors.
+var (
+	infoSchemaTablespacesEncryptedDesc = newNamespacedDesc(
+		informationSchema, "tablespaces_encrypted",
+		"The number of encrypted InnoDB tablespaces.",
+		nil, nil,
+	)
+	infoSchemaTablespacesUnencryptedDesc = newNamespacedDesc(
+		informationSchema, "tablespaces_unencrypted",
+		"The number of unencrypted InnoDB tablespaces.",
+		nil, nil,
+	)
+	infoSchemaSchemaTablespacesDesc = newNamespacedDesc(
+		informationSchema, "schema_tablespaces",
+		"The number of file-per-table InnoDB tablespaces of the schema by whether they are encrypted.",
+		[]string{"schema", "encrypted"}, nil,
+	)
+)
+
+// ScrapeInfoSchemaEncryption collects from `information_schema.INNODB_TABLESPACES`.
+type ScrapeInfoSchemaEncryption struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaEncryption) Name() string {
+	return informationSchema + ".encryption"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaEncryption) Help() string {
+	return "Collect the number of encrypted and unencrypted tablespaces from information_schema.INNODB_TABLESPACES"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaEncryption) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaEncryption) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	encryptionRows, err := db.QueryContext(ctx, infoSchemaEncryptionQuery)
+	if err != nil {
+		return err
+	}
+	defer encryptionRows.Close()
+
+	var (
+		schema, encryption     string
+		count                  float64
+		encrypted, unencrypted float64
+	)
+	for encryptionRows.Next() {
+		if err := encryptionRows.Scan(&schema, &encryption, &count); err != nil {
+			return err
+		}
+		isEncrypted := encryption == "Y"
+		if isEncrypted {
+			encrypted += count
+		} else {
+			unencrypted += count
+		}
+		if *encryptionBySchema && schema != "" {
+			label := "false"
+			if isEncrypted {
+				label = "true"
+			}
+			ch <- prometheus.MustNewConstMetric(
+				infoSchemaSchemaTablespacesDesc, prometheus.GaugeValue, count, schema, label,
+			)
+		}
+	}
+	if err := encryptionRows.Err(); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		infoSchemaTablespacesEncryptedDesc, prometheus.GaugeValue, encrypted,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		infoSchemaTablespacesUnencryptedDesc, prometheus.GaugeValue, unencrypted,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaEncryption{}
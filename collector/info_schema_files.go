@@ -0,0 +1,117 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.files`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Per-table tablespaces are folded into a single series per file type,
+// only shared tablespaces (system, general, undo, temporary) keep their name.
+// They are named schema/table on 8.0 and innodb_file_per_table_N on 5.7.
+const infoSchemaFilesQuery = `
+	SELECT
+	    FILE_TYPE,
+	    CASE
+	      WHEN FILE_TYPE = 'TABLESPACE' AND (TABLESPACE_NAME LIKE '%/%' OR TABLESPACE_NAME LIKE 'innodb_file_per_table_%') THEN ''
+	      ELSE ifnull(TABLESPACE_NAME, '')
+	    END AS TABLESPACE,
+	    SUM(ifnull(TOTAL_EXTENTS, 0)) AS TOTAL_EXTENTS,
+	    SUM(ifnull(FREE_EXTENTS, 0)) AS FREE_EXTENTS,
+	    MAX(ifnull(EXTENT_SIZE, 0)) AS EXTENT_SIZE
+	  FROM information_schema.files
+	  GROUP BY FILE_TYPE, TABLESPACE
+	`
+
+// Metric descriptors.
+var (
+	infoSchemaFilesTotalExtentsDesc = newNamespacedDesc(
+		informationSchema, "files_total_extents",
+		"The number of extents allocated to the files, from information_schema.files.",
+		[]string{"file_type", "tablespace"}, nil,
+	)
+	infoSchemaFilesFreeExtentsDesc = newNamespacedDesc(
+		informationSchema, "files_free_extents",
+		"The number of fully free extents in the files, from information_schema.files.",
+		[]string{"file_type", "tablespace"}, nil,
+	)
+	infoSchemaFilesExtentSizeDesc = newNamespacedDesc(
+		informationSchema, "files_extent_size_bytes",
+		"The extent size of the files in bytes, from information_schema.files.",
+		[]string{"file_type", "tablespace"}, nil,
+	)
+)
+
+// ScrapeInfoSchemaFiles collects from `information_schema.files`.
+type ScrapeInfoSchemaFiles struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaFiles) Name() string {
+	return informationSchema + ".files"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaFiles) Help() string {
+	return "Collect extent allocation per file type from information_schema.files"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaFiles) Version() float64 {
+	return 5.7
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaFiles) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	filesRows, err := db.QueryContext(ctx, infoSchemaFilesQuery)
+	if err != nil {
+		return err
+	}
+	defer filesRows.Close()
+
+	var (
+		fileType, tablespace      string
+		totalExtents, freeExtents uint64
+		extentSize                uint64
+	)
+
+	for filesRows.Next() {
+		if err := filesRows.Scan(
+			&fileType, &tablespace, &totalExtents, &freeExtents, &extentSize,
+		); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaFilesTotalExtentsDesc, prometheus.GaugeValue, float64(totalExtents),
+			fileType, tablespace,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaFilesFreeExtentsDesc, prometheus.GaugeValue, float64(freeExtents),
+			fileType, tablespace,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaFilesExtentSizeDesc, prometheus.GaugeValue, float64(extentSize),
+			fileType, tablespace,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaFiles{}
@@ -0,0 +1,67 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapeInfoSchemaFiles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"FILE_TYPE", "TABLESPACE", "TOTAL_EXTENTS", "FREE_EXTENTS", "EXTENT_SIZE"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("TABLESPACE", "", "120", "3", "1048576").
+		AddRow("UNDO LOG", "innodb_undo_001", "16", "2", "1048576")
+	mock.ExpectQuery(sanitizeQuery(infoSchemaFilesQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInfoSchemaFiles{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"file_type": "TABLESPACE", "tablespace": ""}, value: 120, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"file_type": "TABLESPACE", "tablespace": ""}, value: 3, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"file_type": "TABLESPACE", "tablespace": ""}, value: 1048576, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"file_type": "UNDO LOG", "tablespace": "innodb_undo_001"}, value: 16, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"file_type": "UNDO LOG", "tablespace": "innodb_undo_001"}, value: 2, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"file_type": "UNDO LOG", "tablespace": "innodb_undo_001"}, value: 1048576, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.referential_constraints`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const infoSchemaForeignKeysQuery = `
+	SELECT
+	    CONSTRAINT_SCHEMA,
+	    COUNT(*) AS FOREIGN_KEYS
+	  FROM information_schema.referential_constraints
+	  WHERE %s
+	  GROUP BY CONSTRAINT_SCHEMA
+	`
+
+// Tunable flags.
+var (
+	foreignKeysDatabases = kingpin.Flag(
+		"collect.info_schema.foreign_keys.databases",
+		"The list of databases to collect foreign key counts for, or '*' for all",
+	).Default("*").String()
+)
+
+// Metric descriptors.
+var (
+	infoSchemaForeignKeysDesc = newNamespacedDesc(
+		informationSchema, "foreign_keys",
+		"The number of foreign keys per schema from information_schema.referential_constraints.",
+		[]string{"schema"}, nil,
+	)
+)
+
+// ScrapeInfoSchemaForeignKeys collects from `information_schema.referential_constraints`.
+type ScrapeInfoSchemaForeignKeys struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaForeignKeys) Name() string {
+	return informationSchema + ".foreign_keys"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaForeignKeys) Help() string {
+	return "Collect the number of foreign keys per schema from information_schema.referential_constraints"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaForeignKeys) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaForeignKeys) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	condition, args := schemaFilter("CONSTRAINT_SCHEMA", *foreignKeysDatabases)
+	foreignKeysRows, err := db.QueryContext(ctx, fmt.Sprintf(infoSchemaForeignKeysQuery, condition), args...)
+	if err != nil {
+		return err
+	}
+	defer foreignKeysRows.Close()
+
+	var (
+		schema      string
+		foreignKeys uint64
+	)
+	for foreignKeysRows.Next() {
+		if err := foreignKeysRows.Scan(&schema, &foreignKeys); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaForeignKeysDesc, prometheus.GaugeValue, float64(foreignKeys), schema,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaForeignKeys{}
@@ -0,0 +1,134 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.innodb_sys_indexes`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// InnoDB names tables "schema/table", with a "#P#partition" suffix for
+// partitions, and gives tables without a primary key a hidden
+// GEN_CLUST_INDEX clustered index.
+const indexHygieneQuery = `
+	SELECT
+	    SUBSTRING_INDEX(t.NAME, '/', 1) AS TABLE_SCHEMA,
+	    SUBSTRING_INDEX(SUBSTRING_INDEX(t.NAME, '/', -1), '#', 1) AS TABLE_NAME,
+	    COUNT(DISTINCT CASE WHEN i.NAME <> 'GEN_CLUST_INDEX' THEN i.NAME END) AS INDEXES,
+	    MAX(i.NAME = 'GEN_CLUST_INDEX') AS WITHOUT_PK
+	  FROM information_schema.` + "`%s`" + ` t
+	  JOIN information_schema.` + "`%s`" + ` i ON i.TABLE_ID = t.TABLE_ID
+	  WHERE t.NAME LIKE '%%/%%'
+	    AND %s
+	  GROUP BY TABLE_SCHEMA, TABLE_NAME
+	`
+
+// Tunable flags.
+var (
+	indexHygieneDatabases = kingpin.Flag(
+		"collect.info_schema.index_hygiene.databases",
+		"The list of databases to collect index stats for, or '*' for all",
+	).Default("*").String()
+)
+
+// Metric descriptors.
+var (
+	infoSchemaTableIndexesDesc = newNamespacedDesc(
+		informationSchema, "table_indexes",
+		"The number of indexes of the InnoDB table, including the primary key.",
+		[]string{"schema", "table"}, nil,
+	)
+	infoSchemaTablesWithoutPKDesc = newNamespacedDesc(
+		informationSchema, "tables_without_pk",
+		"Set to 1 for InnoDB tables without a primary key, which are clustered on a hidden row ID instead.",
+		[]string{"schema", "table"}, nil,
+	)
+)
+
+// ScrapeInfoSchemaIndexHygiene collects from `information_schema.innodb_sys_indexes`.
+type ScrapeInfoSchemaIndexHygiene struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaIndexHygiene) Name() string {
+	return informationSchema + ".index_hygiene"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaIndexHygiene) Help() string {
+	return "Collect the number of indexes per table and the tables without primary key from information_schema.innodb_sys_indexes"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaIndexHygiene) Version() float64 {
+	return 5.6
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaIndexHygiene) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var tablesTablename string
+	err := db.QueryRowContext(ctx, innodbTablesTablenameQuery).Scan(&tablesTablename)
+	if err != nil {
+		return err
+	}
+
+	var indexesTablename string
+	switch tablesTablename {
+	case "INNODB_SYS_TABLES":
+		indexesTablename = "INNODB_SYS_INDEXES"
+	case "INNODB_TABLES":
+		indexesTablename = "INNODB_INDEXES"
+	default:
+		return errors.New("couldn't find INNODB_SYS_TABLES or INNODB_TABLES in information_schema")
+	}
+
+	condition, args := schemaFilter("SUBSTRING_INDEX(t.NAME, '/', 1)", *indexHygieneDatabases)
+	query := fmt.Sprintf(indexHygieneQuery, tablesTablename, indexesTablename, condition)
+	indexRows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer indexRows.Close()
+
+	var (
+		schema, table      string
+		indexes, withoutPK uint64
+	)
+	for indexRows.Next() {
+		if err := indexRows.Scan(&schema, &table, &indexes, &withoutPK); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaTableIndexesDesc, prometheus.GaugeValue, float64(indexes),
+			schema, table,
+		)
+		if withoutPK > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				infoSchemaTablesWithoutPKDesc, prometheus.GaugeValue, 1,
+				schema, table,
+			)
+		}
+	}
+	return indexRows.Err()
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaIndexHygiene{}
@@ -0,0 +1,84 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestScrapeInfoSchemaIndexHygiene(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{
+		"--collect.info_schema.index_hygiene.databases", "shop",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(sanitizeQuery(innodbTablesTablenameQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("INNODB_TABLES"))
+
+	columns := []string{"TABLE_SCHEMA", "TABLE_NAME", "INDEXES", "WITHOUT_PK"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("shop", "orders", "3", "0").
+		AddRow("shop", "audit", "0", "1")
+	query := fmt.Sprintf(indexHygieneQuery, "INNODB_TABLES", "INNODB_INDEXES", "SUBSTRING_INDEX(t.NAME, '/', 1) IN (?)")
+	mock.ExpectQuery(sanitizeQuery(query)).WithArgs("shop").WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInfoSchemaIndexHygiene{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_info_schema_table_indexes", MetricResult{labels: labelMap{"schema": "shop", "table": "orders"}, value: 3, metricType: dto.MetricType_GAUGE}},
+		{"mysql_info_schema_table_indexes", MetricResult{labels: labelMap{"schema": "shop", "table": "audit"}, value: 0, metricType: dto.MetricType_GAUGE}},
+		{"mysql_info_schema_tables_without_pk", MetricResult{labels: labelMap{"schema": "shop", "table": "audit"}, value: 1, metricType: dto.MetricType_GAUGE}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape the InnoDB data size from `information_schema.files`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// information_schema.files lists every InnoDB data file: the system, undo
+// and temporary tablespaces as well as the file-per-table ones. Files
+// smaller than an extent count as empty.
+const innodbDataSizeQuery = `
+	SELECT
+	    ifnull(SUM(ifnull(TOTAL_EXTENTS, 0) * ifnull(EXTENT_SIZE, 0)), 0) AS DATA_BYTES
+	  FROM information_schema.files
+	  WHERE ENGINE = 'InnoDB'
+	`
+
+// Metric descriptors.
+var (
+	innodbDataSizeDesc = newNamespacedDesc(
+		"", "estimated_innodb_data_bytes",
+		"Estimated on-disk size of the InnoDB data files in bytes, summed from the extents in information_schema.files. It is an estimate to compare with filesystem usage, not a measure of it.",
+		nil, nil,
+	)
+)
+
+// ScrapeInfoSchemaInnodbDataSize collects from `information_schema.files`.
+type ScrapeInfoSchemaInnodbDataSize struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaInnodbDataSize) Name() string {
+	return informationSchema + ".innodb_data_size"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaInnodbDataSize) Help() string {
+	return "Collect the estimated on-disk size of the InnoDB data files from information_schema.files"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaInnodbDataSize) Version() float64 {
+	return 5.7
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaInnodbDataSize) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var dataBytes float64
+	if err := db.QueryRowContext(ctx, innodbDataSizeQuery).Scan(&dataBytes); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		innodbDataSizeDesc, prometheus.GaugeValue, dataBytes,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaInnodbDataSize{}
@@ -44,23 +44,23 @@ const infoSchemaInnodbMetricsQuery = `
 
 // Metrics descriptors.
 var (
-	infoSchemaBufferPageReadTotalDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "innodb_metrics_buffer_page_read_total"),
+	infoSchemaBufferPageReadTotalDesc = newNamespacedDesc(
+		informationSchema, "innodb_metrics_buffer_page_read_total",
 		"Total number of buffer pages read total.",
 		[]string{"type"}, nil,
 	)
-	infoSchemaBufferPageWrittenTotalDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "innodb_metrics_buffer_page_written_total"),
+	infoSchemaBufferPageWrittenTotalDesc = newNamespacedDesc(
+		informationSchema, "innodb_metrics_buffer_page_written_total",
 		"Total number of buffer pages written total.",
 		[]string{"type"}, nil,
 	)
-	infoSchemaBufferPoolPagesDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "innodb_metrics_buffer_pool_pages"),
+	infoSchemaBufferPoolPagesDesc = newNamespacedDesc(
+		informationSchema, "innodb_metrics_buffer_pool_pages",
 		"Total number of buffer pool pages by state.",
 		[]string{"state"}, nil,
 	)
-	infoSchemaBufferPoolPagesDirtyDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "innodb_metrics_buffer_pool_dirty_pages"),
+	infoSchemaBufferPoolPagesDirtyDesc = newNamespacedDesc(
+		informationSchema, "innodb_metrics_buffer_pool_dirty_pages",
 		"Total number of dirty pages in the buffer pool.",
 		nil, nil,
 	)
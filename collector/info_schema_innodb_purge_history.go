@@ -0,0 +1,78 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape the history list length from `information_schema.innodb_metrics`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const infoSchemaInnodbPurgeHistoryQuery = `
+	SELECT
+	    count
+	  FROM information_schema.innodb_metrics
+	  WHERE name = 'trx_rseg_history_len'
+	`
+
+// Metric descriptors.
+var (
+	infoSchemaInnodbPurgeHistoryLengthDesc = newNamespacedDesc(
+		informationSchema, "innodb_purge_history_length",
+		"The InnoDB history list length (undo log records not yet purged), from the trx_rseg_history_len InnoDB metric.",
+		nil, nil,
+	)
+)
+
+// ScrapeInnodbPurgeHistory collects the history list length from `information_schema.innodb_metrics`.
+type ScrapeInnodbPurgeHistory struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInnodbPurgeHistory) Name() string {
+	return informationSchema + ".innodb_purge_history"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInnodbPurgeHistory) Help() string {
+	return "Collect the InnoDB history list length from information_schema.innodb_metrics"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInnodbPurgeHistory) Version() float64 {
+	return 5.6
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInnodbPurgeHistory) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var historyLength float64
+	err := db.QueryRowContext(ctx, infoSchemaInnodbPurgeHistoryQuery).Scan(&historyLength)
+	if err == sql.ErrNoRows {
+		// The metric is not available on this server.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		infoSchemaInnodbPurgeHistoryLengthDesc, prometheus.GaugeValue, historyLength,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInnodbPurgeHistory{}
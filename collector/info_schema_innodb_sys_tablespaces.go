@@ -49,18 +49,18 @@ const innodbTablespacesQuery = `
 
 // Metric descriptors.
 var (
-	infoSchemaInnodbTablesspaceInfoDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "innodb_tablespace_space_info"),
+	infoSchemaInnodbTablesspaceInfoDesc = newNamespacedDesc(
+		informationSchema, "innodb_tablespace_space_info",
 		"The Tablespace information and Space ID.",
 		[]string{"tablespace_name", "file_format", "row_format", "space_type"}, nil,
 	)
-	infoSchemaInnodbTablesspaceFileSizeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "innodb_tablespace_file_size_bytes"),
+	infoSchemaInnodbTablesspaceFileSizeDesc = newNamespacedDesc(
+		informationSchema, "innodb_tablespace_file_size_bytes",
 		"The apparent size of the file, which represents the maximum size of the file, uncompressed.",
 		[]string{"tablespace_name"}, nil,
 	)
-	infoSchemaInnodbTablesspaceAllocatedSizeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "innodb_tablespace_allocated_size_bytes"),
+	infoSchemaInnodbTablesspaceAllocatedSizeDesc = newNamespacedDesc(
+		informationSchema, "innodb_tablespace_allocated_size_bytes",
 		"The actual size of the file, which is the amount of space allocated on disk.",
 		[]string{"tablespace_name"}, nil,
 	)
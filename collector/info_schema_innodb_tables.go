@@ -0,0 +1,127 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.innodb_sys_tables`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const innodbTablesTablenameQuery = `
+	SELECT
+	    table_name
+	  FROM information_schema.tables
+	  WHERE table_schema = 'information_schema'
+	    AND (table_name = 'INNODB_SYS_TABLES' OR table_name = 'INNODB_TABLES')
+	`
+const innodbTablesQuery = `
+	SELECT
+	    ifnull(ROW_FORMAT, 'NONE') AS ROW_FORMAT,
+	    COUNT(*) AS TABLES
+	  FROM information_schema.` + "`%s`" + `
+	  GROUP BY ROW_FORMAT
+	`
+
+// Metric descriptors.
+var (
+	infoSchemaInnodbTablesDesc = newNamespacedDesc(
+		informationSchema, "innodb_tables",
+		"The number of InnoDB tables by row format.",
+		[]string{"row_format"}, nil,
+	)
+)
+
+// innodbRowFormats are always reported so that legacy formats show up as 0 rather than missing.
+var innodbRowFormats = []string{"Compact", "Compressed", "Dynamic", "Redundant"}
+
+// ScrapeInfoSchemaInnodbTables collects from `information_schema.innodb_sys_tables`.
+type ScrapeInfoSchemaInnodbTables struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaInnodbTables) Name() string {
+	return informationSchema + ".innodb_tables"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaInnodbTables) Help() string {
+	return "Collect the number of InnoDB tables by row format from information_schema.innodb_sys_tables"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaInnodbTables) Version() float64 {
+	return 5.7
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaInnodbTables) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var tablesTablename string
+	err := db.QueryRowContext(ctx, innodbTablesTablenameQuery).Scan(&tablesTablename)
+	if err != nil {
+		return err
+	}
+
+	var query string
+	switch tablesTablename {
+	case "INNODB_SYS_TABLES", "INNODB_TABLES":
+		query = fmt.Sprintf(innodbTablesQuery, tablesTablename)
+	default:
+		return errors.New("couldn't find INNODB_SYS_TABLES or INNODB_TABLES in information_schema")
+	}
+
+	tablesRows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer tablesRows.Close()
+
+	rowFormats := append([]string{}, innodbRowFormats...)
+	counts := map[string]uint64{}
+	for _, rowFormat := range rowFormats {
+		counts[rowFormat] = 0
+	}
+	var (
+		rowFormat string
+		tables    uint64
+	)
+	for tablesRows.Next() {
+		if err := tablesRows.Scan(&rowFormat, &tables); err != nil {
+			return err
+		}
+		if _, ok := counts[rowFormat]; !ok {
+			rowFormats = append(rowFormats, rowFormat)
+		}
+		counts[rowFormat] += tables
+	}
+	if err := tablesRows.Err(); err != nil {
+		return err
+	}
+
+	for _, rowFormat := range rowFormats {
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaInnodbTablesDesc, prometheus.GaugeValue, float64(counts[rowFormat]),
+			rowFormat,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaInnodbTables{}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapeInfoSchemaInnodbTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(sanitizeQuery(innodbTablesTablenameQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("INNODB_TABLES"))
+	columns := []string{"ROW_FORMAT", "TABLES"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("Dynamic", "412").
+		AddRow("Compact", "7").
+		AddRow("NONE", "2")
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(innodbTablesQuery, "INNODB_TABLES"))).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInfoSchemaInnodbTables{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"row_format": "Compact"}, value: 7, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"row_format": "Compressed"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"row_format": "Dynamic"}, value: 412, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"row_format": "Redundant"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"row_format": "NONE"}, value: 2, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.INNODB_SESSION_TEMP_TABLESPACES` and `information_schema.INNODB_TEMP_TABLE_INFO`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Session temporary tablespaces are ACTIVE while allocated to a session,
+// and INACTIVE once returned to the pool, truncated.
+const innodbTempTablespacesQuery = `
+	SELECT
+	    STATE,
+	    SUM(SIZE) AS SIZE
+	  FROM information_schema.INNODB_SESSION_TEMP_TABLESPACES
+	  GROUP BY STATE
+	`
+
+const innodbTempTablesQuery = `SELECT COUNT(*) FROM information_schema.INNODB_TEMP_TABLE_INFO`
+
+// Metric descriptors.
+var (
+	infoSchemaInnodbTempTablespaceSizeDesc = newNamespacedDesc(
+		informationSchema, "innodb_temp_tablespace_size_bytes",
+		"The size of the InnoDB session temporary tablespaces in bytes, by state.",
+		[]string{"state"}, nil,
+	)
+	infoSchemaInnodbTempTablesDesc = newNamespacedDesc(
+		informationSchema, "innodb_temp_tables",
+		"The number of active user-created InnoDB temporary tables.",
+		nil, nil,
+	)
+)
+
+// ScrapeInfoSchemaInnodbTemp collects from `information_schema.INNODB_SESSION_TEMP_TABLESPACES`
+// and `information_schema.INNODB_TEMP_TABLE_INFO`.
+type ScrapeInfoSchemaInnodbTemp struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaInnodbTemp) Name() string {
+	return informationSchema + ".innodb_temp"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaInnodbTemp) Help() string {
+	return "Collect the InnoDB session temporary tablespaces size and the number of temporary tables from information_schema"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaInnodbTemp) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaInnodbTemp) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	tablespacesRows, err := db.QueryContext(ctx, innodbTempTablespacesQuery)
+	if err != nil {
+		return err
+	}
+	defer tablespacesRows.Close()
+
+	var (
+		state string
+		size  float64
+	)
+	for tablespacesRows.Next() {
+		if err := tablespacesRows.Scan(&state, &size); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaInnodbTempTablespaceSizeDesc, prometheus.GaugeValue, size, state,
+		)
+	}
+	if err := tablespacesRows.Err(); err != nil {
+		return err
+	}
+	tablespacesRows.Close()
+
+	var tables float64
+	if err := db.QueryRowContext(ctx, innodbTempTablesQuery).Scan(&tables); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		infoSchemaInnodbTempTablesDesc, prometheus.GaugeValue, tables,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaInnodbTemp{}
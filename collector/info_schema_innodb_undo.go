@@ -0,0 +1,112 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape the undo tablespaces from `information_schema.INNODB_TABLESPACES`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Undo tablespaces are active, inactive while being truncated, or empty
+// once truncated after being set inactive by hand.
+const innodbUndoTablespacesQuery = `
+	SELECT
+	    NAME,
+	    STATE,
+	    FILE_SIZE
+	  FROM information_schema.INNODB_TABLESPACES
+	  WHERE SPACE_TYPE = 'Undo'
+	`
+
+// Metric descriptors.
+var (
+	innodbUndoTablespacesDesc = newNamespacedDesc(
+		innodbStatus, "undo_tablespaces",
+		"The number of InnoDB undo tablespaces.",
+		nil, nil,
+	)
+	innodbUndoTablespaceSizeDesc = newNamespacedDesc(
+		innodbStatus, "undo_tablespace_size_bytes",
+		"The size of the InnoDB undo tablespace file in bytes.",
+		[]string{"tablespace"}, nil,
+	)
+	innodbUndoTruncateActiveDesc = newNamespacedDesc(
+		innodbStatus, "undo_truncate_active",
+		"Whether the InnoDB undo tablespace is inactive for truncation (1 for inactive, 0 otherwise). A tablespace that keeps growing without ever being truncated fills the disk.",
+		[]string{"tablespace"}, nil,
+	)
+)
+
+// ScrapeInfoSchemaInnodbUndo collects from `information_schema.INNODB_TABLESPACES`.
+type ScrapeInfoSchemaInnodbUndo struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaInnodbUndo) Name() string {
+	return informationSchema + ".innodb_undo"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaInnodbUndo) Help() string {
+	return "Collect the InnoDB undo tablespaces size and truncation from information_schema.INNODB_TABLESPACES"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaInnodbUndo) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaInnodbUndo) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	undoRows, err := db.QueryContext(ctx, innodbUndoTablespacesQuery)
+	if err != nil {
+		return err
+	}
+	defer undoRows.Close()
+
+	var (
+		name, state       string
+		size, tablespaces float64
+	)
+	for undoRows.Next() {
+		if err := undoRows.Scan(&name, &state, &size); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			innodbUndoTablespaceSizeDesc, prometheus.GaugeValue, size, name,
+		)
+		truncating := 0.0
+		if state == "inactive" {
+			truncating = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			innodbUndoTruncateActiveDesc, prometheus.GaugeValue, truncating, name,
+		)
+		tablespaces++
+	}
+	if err := undoRows.Err(); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		innodbUndoTablespacesDesc, prometheus.GaugeValue, tablespaces,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaInnodbUndo{}
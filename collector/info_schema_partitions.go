@@ -0,0 +1,118 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.partitions`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Subpartitions are summed into their partition.
+const infoSchemaPartitionsQuery = `
+	SELECT
+	    TABLE_SCHEMA,
+	    TABLE_NAME,
+	    PARTITION_NAME,
+	    SUM(ifnull(TABLE_ROWS, 0)) AS TABLE_ROWS,
+	    SUM(ifnull(DATA_LENGTH, 0)) AS DATA_LENGTH
+	  FROM information_schema.partitions
+	  WHERE PARTITION_NAME IS NOT NULL
+	    AND %s
+	  GROUP BY TABLE_SCHEMA, TABLE_NAME, PARTITION_NAME
+	  ORDER BY DATA_LENGTH DESC
+	  %s
+	`
+
+// Tunable flags.
+var (
+	partitionsDatabases = kingpin.Flag(
+		"collect.info_schema.partitions.databases",
+		"The list of databases to collect partition stats for, or '*' for all",
+	).Default("*").String()
+	partitionsLimit = kingpin.Flag(
+		"collect.info_schema.partitions.limit",
+		"Limit the number of partitions collected, largest first, 0 for no limit",
+	).Default("1000").Int()
+)
+
+// Metric descriptors.
+var (
+	infoSchemaPartitionRowsDesc = newNamespacedDesc(
+		informationSchema, "partition_rows",
+		"The estimated number of rows in the partition from information_schema.partitions.",
+		[]string{"schema", "table", "partition"}, nil,
+	)
+	infoSchemaPartitionDataBytesDesc = newNamespacedDesc(
+		informationSchema, "partition_data_bytes",
+		"The data length of the partition in bytes from information_schema.partitions.",
+		[]string{"schema", "table", "partition"}, nil,
+	)
+)
+
+// ScrapeInfoSchemaPartitions collects from `information_schema.partitions`.
+type ScrapeInfoSchemaPartitions struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaPartitions) Name() string {
+	return informationSchema + ".partitions"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaPartitions) Help() string {
+	return "Collect rows and data size per partition from information_schema.partitions"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaPartitions) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaPartitions) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	condition, args := schemaFilter("TABLE_SCHEMA", *partitionsDatabases)
+	partitionsRows, err := db.QueryContext(ctx, fmt.Sprintf(infoSchemaPartitionsQuery, condition, limitClause(*partitionsLimit)), args...)
+	if err != nil {
+		return err
+	}
+	defer partitionsRows.Close()
+
+	var (
+		schema, table, partition string
+		rows, dataLength         uint64
+	)
+	for partitionsRows.Next() {
+		if err := partitionsRows.Scan(&schema, &table, &partition, &rows, &dataLength); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaPartitionRowsDesc, prometheus.GaugeValue, float64(rows),
+			schema, table, partition,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaPartitionDataBytesDesc, prometheus.GaugeValue, float64(dataLength),
+			schema, table, partition,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaPartitions{}
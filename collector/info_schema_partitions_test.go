@@ -0,0 +1,78 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestScrapeInfoSchemaPartitions(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{
+		"--collect.info_schema.partitions.databases", "logs",
+		"--collect.info_schema.partitions.limit", "2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"TABLE_SCHEMA", "TABLE_NAME", "PARTITION_NAME", "TABLE_ROWS", "DATA_LENGTH"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("logs", "events", "p202109", "900000", "104857600").
+		AddRow("logs", "events", "p202110", "1200", "163840")
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(infoSchemaPartitionsQuery, "TABLE_SCHEMA IN (?)", "LIMIT 2"))).
+		WithArgs("logs").
+		WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInfoSchemaPartitions{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"schema": "logs", "table": "events", "partition": "p202109"}, value: 900000, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"schema": "logs", "table": "events", "partition": "p202109"}, value: 104857600, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"schema": "logs", "table": "events", "partition": "p202110"}, value: 1200, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"schema": "logs", "table": "events", "partition": "p202110"}, value: 163840, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
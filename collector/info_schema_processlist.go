@@ -59,20 +59,20 @@ var (
 
 // Metric descriptors.
 var (
-	processlistCountDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "threads"),
+	processlistCountDesc = newNamespacedDesc(
+		informationSchema, "threads",
 		"The number of threads (connections) split by current state.",
 		[]string{"state"}, nil)
-	processlistTimeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "threads_seconds"),
+	processlistTimeDesc = newNamespacedDesc(
+		informationSchema, "threads_seconds",
 		"The number of seconds threads (connections) have used split by current state.",
 		[]string{"state"}, nil)
-	processesByUserDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "processes_by_user"),
+	processesByUserDesc = newNamespacedDesc(
+		informationSchema, "processes_by_user",
 		"The number of processes by user.",
 		[]string{"mysql_user"}, nil)
-	processesByHostDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "processes_by_host"),
+	processesByHostDesc = newNamespacedDesc(
+		informationSchema, "processes_by_host",
 		"The number of processes by host.",
 		[]string{"client_host"}, nil)
 )
@@ -0,0 +1,116 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.triggers` and `information_schema.views`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const infoSchemaTriggersQuery = `
+	SELECT
+	    TRIGGER_SCHEMA,
+	    COUNT(*) AS TRIGGERS
+	  FROM information_schema.triggers
+	  WHERE %s
+	  GROUP BY TRIGGER_SCHEMA
+	`
+const infoSchemaViewsQuery = `
+	SELECT
+	    TABLE_SCHEMA,
+	    COUNT(*) AS VIEWS
+	  FROM information_schema.views
+	  WHERE %s
+	  GROUP BY TABLE_SCHEMA
+	`
+
+// Metric descriptors.
+var (
+	infoSchemaTriggersDesc = newNamespacedDesc(
+		informationSchema, "triggers",
+		"The number of triggers per schema from information_schema.triggers.",
+		[]string{"schema"}, nil,
+	)
+	infoSchemaViewsDesc = newNamespacedDesc(
+		informationSchema, "views",
+		"The number of views per schema from information_schema.views.",
+		[]string{"schema"}, nil,
+	)
+)
+
+// ScrapeInfoSchemaSchemaComplexity collects from `information_schema.triggers` and `information_schema.views`.
+type ScrapeInfoSchemaSchemaComplexity struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaSchemaComplexity) Name() string {
+	return informationSchema + ".schema_complexity"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaSchemaComplexity) Help() string {
+	return "Collect the number of triggers and views per schema from information_schema"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaSchemaComplexity) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaSchemaComplexity) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	// Use the same databases as the tables collector.
+	for _, count := range []struct {
+		query, column string
+		desc          *prometheus.Desc
+	}{
+		{infoSchemaTriggersQuery, "TRIGGER_SCHEMA", infoSchemaTriggersDesc},
+		{infoSchemaViewsQuery, "TABLE_SCHEMA", infoSchemaViewsDesc},
+	} {
+		condition, args := schemaFilter(count.column, *tableSchemaDatabases)
+		if err := scrapeSchemaCounts(ctx, db, ch, fmt.Sprintf(count.query, condition), args, count.desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scrapeSchemaCounts sends the (schema, count) rows returned by query as gauges.
+func scrapeSchemaCounts(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, query string, args []interface{}, desc *prometheus.Desc) error {
+	countRows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer countRows.Close()
+
+	var (
+		schema string
+		count  uint64
+	)
+	for countRows.Next() {
+		if err := countRows.Scan(&schema, &count); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), schema)
+	}
+	return countRows.Err()
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaSchemaComplexity{}
@@ -0,0 +1,114 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `mysql.innodb_table_stats`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// InnoDB keeps the persistent statistics of partitions under the
+// "table#p#partition" name.
+const tableStatsStalenessQuery = `
+	SELECT
+	    database_name,
+	    table_name,
+	    TIMESTAMPDIFF(SECOND, last_update, NOW()) AS age
+	  FROM mysql.innodb_table_stats
+	  WHERE last_update < NOW() - INTERVAL ? SECOND
+	    AND %s
+	`
+
+// Tunable flags.
+var (
+	tableStatsStalenessThreshold = kingpin.Flag(
+		"collect.info_schema.table_stats_staleness.threshold",
+		"Age from which the persistent statistics of a table are stale",
+	).Default("168h").Duration()
+)
+
+// Metric descriptors.
+var (
+	infoSchemaStaleTableStatsDesc = newNamespacedDesc(
+		informationSchema, "stale_table_stats",
+		"Age in seconds of the persistent statistics of the InnoDB tables not analyzed within the threshold.",
+		[]string{"schema", "table"}, nil,
+	)
+	infoSchemaStaleTableStatsTotalDesc = newNamespacedDesc(
+		informationSchema, "stale_table_stats_total",
+		"The number of InnoDB tables whose persistent statistics were not updated within the threshold.",
+		nil, nil,
+	)
+)
+
+// ScrapeInfoSchemaTableStatsStaleness collects from `mysql.innodb_table_stats`.
+type ScrapeInfoSchemaTableStatsStaleness struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInfoSchemaTableStatsStaleness) Name() string {
+	return informationSchema + ".table_stats_staleness"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInfoSchemaTableStatsStaleness) Help() string {
+	return "Collect the InnoDB tables whose persistent statistics are older than the threshold from mysql.innodb_table_stats"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInfoSchemaTableStatsStaleness) Version() float64 {
+	return 5.6
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInfoSchemaTableStatsStaleness) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	condition, args := schemaFilter("database_name", "*")
+	query := fmt.Sprintf(tableStatsStalenessQuery, condition)
+	args = append([]interface{}{int64(tableStatsStalenessThreshold.Seconds())}, args...)
+	staleRows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer staleRows.Close()
+
+	var (
+		schema, table string
+		age, stale    float64
+	)
+	for staleRows.Next() {
+		if err := staleRows.Scan(&schema, &table, &age); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			infoSchemaStaleTableStatsDesc, prometheus.GaugeValue, age, schema, table,
+		)
+		stale++
+	}
+	if err := staleRows.Err(); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		infoSchemaStaleTableStatsTotalDesc, prometheus.GaugeValue, stale,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeInfoSchemaTableStatsStaleness{}
@@ -57,8 +57,16 @@ var (
 		"collect.info_schema.tables.databases",
 		"The list of databases to collect table stats for, or '*' for all",
 	).Default("*").String()
+	tableSchemaColumns = kingpin.Flag(
+		"collect.info_schema.tables.columns",
+		"Comma-separated list of table components to collect, from "+strings.Join(tableSchemaComponents, ","),
+	).Default(strings.Join(tableSchemaComponents, ",")).Action(validateTableSchemaColumns).String()
 )
 
+// tableSchemaComponents lists the components of information_schema.tables
+// that can be selected with --collect.info_schema.tables.columns.
+var tableSchemaComponents = []string{"table_rows", "data_length", "index_length", "data_free"}
+
 // Metric descriptors.
 var (
 	// infoSchemaTablesVersionDesc = prometheus.NewDesc(
@@ -66,18 +74,55 @@ var (
 	// 	"The version number of the table's .frm file",
 	// 	[]string{"schema", "table", "type", "engine", "row_format", "create_options"}, nil,
 	// )
-	infoSchemaTablesRowsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "table_rows"),
+	infoSchemaTablesRowsDesc = newNamespacedDesc(
+		informationSchema, "table_rows",
 		"The estimated number of rows in the table from information_schema.tables",
 		[]string{"schema", "table"}, nil,
 	)
-	infoSchemaTablesSizeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, informationSchema, "table_size"),
+	infoSchemaTablesSizeDesc = newNamespacedDesc(
+		informationSchema, "table_size",
 		"The size of the table components from information_schema.tables",
 		[]string{"schema", "table", "component"}, nil,
 	)
 )
 
+// parseTableSchemaColumns returns the set of selected table components.
+func parseTableSchemaColumns(columns string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+		known := false
+		for _, component := range tableSchemaComponents {
+			if column == component {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown table component %q", column)
+		}
+		selected[column] = true
+	}
+	return selected, nil
+}
+
+// validateTableSchemaColumns rejects unknown components at startup.
+func validateTableSchemaColumns(ctx *kingpin.ParseContext) error {
+	for _, element := range ctx.Elements {
+		flag, ok := element.Clause.(*kingpin.FlagClause)
+		if !ok || flag.Model().Name != "collect.info_schema.tables.columns" || element.Value == nil {
+			continue
+		}
+		if _, err := parseTableSchemaColumns(*element.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ScrapeTableSchema collects from `information_schema.tables`.
 type ScrapeTableSchema struct{}
 
@@ -98,6 +143,11 @@ func (ScrapeTableSchema) Version() float64 {
 
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeTableSchema) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	columns, err := parseTableSchemaColumns(*tableSchemaColumns)
+	if err != nil {
+		return err
+	}
+
 	var dbList []string
 	if *tableSchemaDatabases == "*" {
 		dbListRows, err := db.QueryContext(ctx, dbListQuery)
@@ -162,22 +212,29 @@ func (ScrapeTableSchema) Scrape(ctx context.Context, db *sql.DB, ch chan<- prome
 			// 	infoSchemaTablesVersionDesc, prometheus.GaugeValue, float64(version),
 			// 	tableSchema, tableName, tableType, engine, rowFormat, createOptions,
 			// )
-			ch <- prometheus.MustNewConstMetric(
-				infoSchemaTablesRowsDesc, prometheus.GaugeValue, float64(tableRows),
-				tableSchema, tableName,
-			)
-			ch <- prometheus.MustNewConstMetric(
-				infoSchemaTablesSizeDesc, prometheus.GaugeValue, float64(dataLength),
-				tableSchema, tableName, "data_length",
-			)
-			ch <- prometheus.MustNewConstMetric(
-				infoSchemaTablesSizeDesc, prometheus.GaugeValue, float64(indexLength),
-				tableSchema, tableName, "index_length",
-			)
-			ch <- prometheus.MustNewConstMetric(
-				infoSchemaTablesSizeDesc, prometheus.GaugeValue, float64(dataFree),
-				tableSchema, tableName, "data_free",
-			)
+			if columns["table_rows"] {
+				ch <- prometheus.MustNewConstMetric(
+					infoSchemaTablesRowsDesc, prometheus.GaugeValue, float64(tableRows),
+					tableSchema, tableName,
+				)
+			}
+			sizes := []struct {
+				component string
+				value     uint64
+			}{
+				{"data_length", dataLength},
+				{"index_length", indexLength},
+				{"data_free", dataFree},
+			}
+			for _, size := range sizes {
+				if !columns[size.component] {
+					continue
+				}
+				ch <- prometheus.MustNewConstMetric(
+					infoSchemaTablesSizeDesc, prometheus.GaugeValue, float64(size.value),
+					tableSchema, tableName, size.component,
+				)
+			}
 		}
 	}
 
@@ -0,0 +1,92 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestScrapeTableSchemaColumns(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{
+		"--collect.info_schema.tables.databases", "shop",
+		"--collect.info_schema.tables.columns", "data_length,index_length",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"TABLE_SCHEMA", "TABLE_NAME", "TABLE_TYPE", "ENGINE", "VERSION", "ROW_FORMAT", "TABLE_ROWS", "DATA_LENGTH", "INDEX_LENGTH", "DATA_FREE", "CREATE_OPTIONS"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("shop", "orders", "BASE TABLE", "InnoDB", "10", "Dynamic", "1000", "16384", "8192", "4096", "")
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(tableSchemaQuery, "shop"))).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeTableSchema{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"schema": "shop", "table": "orders", "component": "data_length"}, value: 16384, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"schema": "shop", "table": "orders", "component": "index_length"}, value: 8192, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestParseTableSchemaColumns(t *testing.T) {
+	if _, err := parseTableSchemaColumns("data_length,auto_increment"); err == nil {
+		t.Error("expected an error for an unknown component")
+	}
+	if _, err := kingpin.CommandLine.Parse([]string{"--collect.info_schema.tables.columns", "rows"}); err == nil {
+		t.Error("expected the flag to be rejected at parse time")
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+	columns, err := parseTableSchemaColumns("table_rows, data_free")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 2 || !columns["table_rows"] || !columns["data_free"] {
+		t.Errorf("unexpected columns: %v", columns)
+	}
+}
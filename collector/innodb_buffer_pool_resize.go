@@ -0,0 +1,152 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `Innodb_buffer_pool_resize_status`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Also matches Innodb_buffer_pool_resize_status_code and
+// Innodb_buffer_pool_resize_status_progress from MySQL 8.0.31.
+const innodbBufferPoolResizeQuery = `SHOW GLOBAL STATUS LIKE 'Innodb_buffer_pool_resize_status%'`
+
+// Stages of Innodb_buffer_pool_resize_status_code: 0 when no resize is in
+// progress, 1 to 6 for the steps of a resize and 7 when it failed.
+const (
+	bufferPoolResizeNone   = 0
+	bufferPoolResizeFailed = 7
+)
+
+// Metric descriptors.
+var (
+	innodbBufferPoolResizeInProgressDesc = newNamespacedDesc(
+		innodbStatus, "buffer_pool_resize_in_progress",
+		"Whether an online resize of the InnoDB buffer pool is in progress, a resize stuck for long is an incident.",
+		nil, nil,
+	)
+	innodbBufferPoolResizeFailedDesc = newNamespacedDesc(
+		innodbStatus, "buffer_pool_resize_failed",
+		"Whether the last online resize of the InnoDB buffer pool failed.",
+		nil, nil,
+	)
+)
+
+// ScrapeInnodbBufferPoolResize collects from `Innodb_buffer_pool_resize_status`.
+type ScrapeInnodbBufferPoolResize struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInnodbBufferPoolResize) Name() string {
+	return "innodb_buffer_pool_resize"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInnodbBufferPoolResize) Help() string {
+	return "Collect whether an InnoDB buffer pool resize is in progress or failed from Innodb_buffer_pool_resize_status"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInnodbBufferPoolResize) Version() float64 {
+	return 5.7
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeInnodbBufferPoolResize) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	resizeRows, err := db.QueryContext(ctx, innodbBufferPoolResizeQuery)
+	if err != nil {
+		return err
+	}
+	defer resizeRows.Close()
+
+	var (
+		name, value        string
+		status             string
+		hasStatus, hasCode bool
+		code               int
+	)
+	for resizeRows.Next() {
+		if err := resizeRows.Scan(&name, &value); err != nil {
+			return err
+		}
+		switch strings.ToLower(name) {
+		case "innodb_buffer_pool_resize_status":
+			status, hasStatus = value, true
+		case "innodb_buffer_pool_resize_status_code":
+			if code, err = strconv.Atoi(value); err == nil {
+				hasCode = true
+			}
+		}
+	}
+	if err := resizeRows.Err(); err != nil {
+		return err
+	}
+
+	var inProgress, failed float64
+	switch {
+	case hasCode:
+		// The status code doesn't depend on the wording of the status.
+		if code == bufferPoolResizeFailed {
+			failed = 1
+		} else if code != bufferPoolResizeNone {
+			inProgress = 1
+		}
+	case hasStatus:
+		if bufferPoolResizeHasFailed(status) {
+			failed = 1
+		} else if bufferPoolResizeInProgress(status) {
+			inProgress = 1
+		}
+	default:
+		return nil
+	}
+	ch <- prometheus.MustNewConstMetric(
+		innodbBufferPoolResizeInProgressDesc, prometheus.GaugeValue, inProgress,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		innodbBufferPoolResizeFailedDesc, prometheus.GaugeValue, failed,
+	)
+	return nil
+}
+
+// bufferPoolResizeInProgress parses Innodb_buffer_pool_resize_status. It is
+// empty until the first resize, then shows the current step of the resize
+// (e.g. "Withdrawing blocks to be shrunken.") until it ends with either
+// "Completed resizing buffer pool at 210623 10:49:21." or, when
+// innodb_buffer_pool_size was set to its current value,
+// "Size did not change (old size = new size = 134217728. Nothing to do.".
+// A resize that failed, e.g. with "buffer pool 0 : failed to allocate new
+// memory.", is not in progress anymore.
+func bufferPoolResizeInProgress(status string) bool {
+	status = strings.TrimSpace(status)
+	return status != "" &&
+		!strings.HasPrefix(status, "Completed") &&
+		!strings.HasPrefix(status, "Size did not change") &&
+		!bufferPoolResizeHasFailed(status)
+}
+
+// bufferPoolResizeHasFailed tells whether Innodb_buffer_pool_resize_status
+// reports a failure.
+func bufferPoolResizeHasFailed(status string) bool {
+	return strings.Contains(strings.ToLower(status), "fail")
+}
+
+// check interface
+var _ Scraper = ScrapeInnodbBufferPoolResize{}
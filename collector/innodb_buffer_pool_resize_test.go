@@ -0,0 +1,127 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapeInnodbBufferPoolResize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Variable_name", "Value"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("Innodb_buffer_pool_resize_status", "buffer pool 0 : withdrawing blocks. (2048/8192)")
+	mock.ExpectQuery(sanitizeQuery(innodbBufferPoolResizeQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInnodbBufferPoolResize{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeInnodbBufferPoolResizeStatusCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// MySQL 8.0.31+, the status code takes precedence over the status.
+	columns := []string{"Variable_name", "Value"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("Innodb_buffer_pool_resize_status", "Resizing buffer pool failed.").
+		AddRow("Innodb_buffer_pool_resize_status_code", "7").
+		AddRow("Innodb_buffer_pool_resize_status_progress", "0")
+	mock.ExpectQuery(sanitizeQuery(innodbBufferPoolResizeQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInnodbBufferPoolResize{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestBufferPoolResizeInProgress(t *testing.T) {
+	convey.Convey("Buffer pool resize status", t, func() {
+		for status, inProgress := range map[string]bool{
+			"": false,
+			"Resizing buffer pool from 134217728 (new size: 268435456 bytes)":      true,
+			"Latching whole of buffer pool.":                                       true,
+			"Completed resizing buffer pool at 210623 10:49:21.":                   false,
+			"Size did not change (old size = new size = 134217728. Nothing to do.": false,
+			"buffer pool 0 : failed to allocate new memory.":                       false,
+		} {
+			convey.So(bufferPoolResizeInProgress(status), convey.ShouldEqual, inProgress)
+		}
+		for status, failed := range map[string]bool{
+			"": false,
+			"buffer pool 0 : withdrawing blocks. (2048/8192)":    false,
+			"Completed resizing buffer pool at 210623 10:49:21.": false,
+			"buffer pool 0 : failed to allocate new memory.":     true,
+		} {
+			convey.So(bufferPoolResizeHasFailed(status), convey.ShouldEqual, failed)
+		}
+	})
+}
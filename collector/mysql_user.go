@@ -80,20 +80,20 @@ var (
 
 // Metric descriptors.
 var (
-	userMaxQuestionsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, mysql, "max_questions"),
+	userMaxQuestionsDesc = newNamespacedDesc(
+		mysql, "max_questions",
 		"The number of max_questions by user.",
 		labelNames, nil)
-	userMaxUpdatesDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, mysql, "max_updates"),
+	userMaxUpdatesDesc = newNamespacedDesc(
+		mysql, "max_updates",
 		"The number of max_updates by user.",
 		labelNames, nil)
-	userMaxConnectionsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, mysql, "max_connections"),
+	userMaxConnectionsDesc = newNamespacedDesc(
+		mysql, "max_connections",
 		"The number of max_connections by user.",
 		labelNames, nil)
-	userMaxUserConnectionsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, mysql, "max_user_connections"),
+	userMaxUserConnectionsDesc = newNamespacedDesc(
+		mysql, "max_user_connections",
 		"The number of max_user_connections by user.",
 		labelNames, nil)
 )
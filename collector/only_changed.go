@@ -0,0 +1,94 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Tunable flags.
+var (
+	collectOnlyChanged = kingpin.Flag(
+		"collect.only-changed",
+		"EXPERIMENTAL: Skip gauges whose value didn't change since the previous scrape. Counters and other metrics are always collected. Skipped series go stale in Prometheus, only use this when the consumer keeps the last value.",
+	).Default("false").Bool()
+)
+
+// gaugeValues keeps the last value of each gauge series across scrapes, by
+// set of scrapers: a collect[] request must not change what the next scrape
+// with other scrapers considers unchanged.
+type gaugeValues struct {
+	mu   sync.Mutex
+	last map[string]map[string]float64
+}
+
+func newGaugeValues() *gaugeValues {
+	return &gaugeValues{last: map[string]map[string]float64{}}
+}
+
+// forward sends the metrics from in to out until in is closed, dropping the
+// gauges with the same value as in the previous scrape of the same scrapers.
+// Series that were not seen in this scrape are forgotten.
+func (g *gaugeValues) forward(scrapers []Scraper, in <-chan prometheus.Metric, out chan<- prometheus.Metric) {
+	setKey := scrapersKey(scrapers)
+	g.mu.Lock()
+	last := g.last[setKey]
+	g.mu.Unlock()
+
+	seen := map[string]float64{}
+	for m := range in {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil || pb.Gauge == nil {
+			out <- m
+			continue
+		}
+		key := seriesKey(m.Desc(), pb.Label)
+		value := pb.Gauge.GetValue()
+		seen[key] = value
+		if previous, ok := last[key]; ok && previous == value {
+			continue
+		}
+		out <- m
+	}
+
+	g.mu.Lock()
+	g.last[setKey] = seen
+	g.mu.Unlock()
+}
+
+// scrapersKey identifies a set of scrapers whatever their order.
+func scrapersKey(scrapers []Scraper) string {
+	names := make([]string, 0, len(scrapers))
+	for _, scraper := range scrapers {
+		names = append(names, scraper.Name())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// seriesKey identifies a series by its descriptor and label values.
+func seriesKey(desc *prometheus.Desc, labels []*dto.LabelPair) string {
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		pairs = append(pairs, l.GetName()+"="+l.GetValue())
+	}
+	sort.Strings(pairs)
+	return desc.String() + "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestGaugeValuesForward(t *testing.T) {
+	gaugeDesc := prometheus.NewDesc("test_gauge", "Test gauge.", []string{"name"}, nil)
+	counterDesc := prometheus.NewDesc("test_total", "Test counter.", nil, nil)
+
+	gauges := newGaugeValues()
+	scrape := func(metrics ...prometheus.Metric) []float64 {
+		in := make(chan prometheus.Metric)
+		out := make(chan prometheus.Metric, len(metrics))
+		go func() {
+			for _, m := range metrics {
+				in <- m
+			}
+			close(in)
+		}()
+		gauges.forward([]Scraper{ScrapeGlobalStatus{}}, in, out)
+		close(out)
+
+		var values []float64
+		for m := range out {
+			values = append(values, readMetric(m).value)
+		}
+		return values
+	}
+
+	for i, tc := range []struct {
+		metrics []prometheus.Metric
+		want    []float64
+	}{
+		{
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstMetric(gaugeDesc, prometheus.GaugeValue, 1, "a"),
+				prometheus.MustNewConstMetric(gaugeDesc, prometheus.GaugeValue, 2, "b"),
+				prometheus.MustNewConstMetric(counterDesc, prometheus.CounterValue, 3),
+			},
+			want: []float64{1, 2, 3},
+		},
+		{
+			// Unchanged gauge "a" is dropped, counters are always sent.
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstMetric(gaugeDesc, prometheus.GaugeValue, 1, "a"),
+				prometheus.MustNewConstMetric(gaugeDesc, prometheus.GaugeValue, 5, "b"),
+				prometheus.MustNewConstMetric(counterDesc, prometheus.CounterValue, 3),
+			},
+			want: []float64{5, 3},
+		},
+		{
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstMetric(gaugeDesc, prometheus.GaugeValue, 5, "b"),
+			},
+			want: nil,
+		},
+		{
+			// "a" was missing from the previous scrape, so it is sent again.
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstMetric(gaugeDesc, prometheus.GaugeValue, 1, "a"),
+			},
+			want: []float64{1},
+		},
+	} {
+		if got := scrape(tc.metrics...); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("scrape %d: got %v, want %v", i, got, tc.want)
+		}
+	}
+}
+
+func TestGaugeValuesForwardScraperSets(t *testing.T) {
+	statusDesc := prometheus.NewDesc("test_status", "Test status gauge.", nil, nil)
+	variablesDesc := prometheus.NewDesc("test_variables", "Test variables gauge.", nil, nil)
+	all := []Scraper{ScrapeGlobalStatus{}, ScrapeGlobalVariables{}}
+	filtered := []Scraper{ScrapeGlobalVariables{}}
+
+	gauges := newGaugeValues()
+	// start forwards the metrics of a scrape which only ends once its
+	// returned function is called, so that scrapes can overlap.
+	start := func(scrapers []Scraper, metrics ...prometheus.Metric) func() []float64 {
+		in := make(chan prometheus.Metric)
+		out := make(chan prometheus.Metric, len(metrics))
+		done := make(chan struct{})
+		go func() {
+			gauges.forward(scrapers, in, out)
+			close(out)
+			close(done)
+		}()
+		for _, m := range metrics {
+			in <- m
+		}
+		return func() []float64 {
+			close(in)
+			<-done
+			var values []float64
+			for m := range out {
+				values = append(values, readMetric(m).value)
+			}
+			return values
+		}
+	}
+	status := prometheus.MustNewConstMetric(statusDesc, prometheus.GaugeValue, 1)
+	variables := prometheus.MustNewConstMetric(variablesDesc, prometheus.GaugeValue, 2)
+
+	full := start(all, status, variables)
+	// A collect[] request overlapping the full scrape.
+	filteredScrape := start(filtered, variables)
+	if got := full(); !reflect.DeepEqual(got, []float64{1, 2}) {
+		t.Errorf("first full scrape: got %v, want [1 2]", got)
+	}
+	if got := filteredScrape(); !reflect.DeepEqual(got, []float64{2}) {
+		t.Errorf("first filtered scrape: got %v, want [2]", got)
+	}
+
+	// The filtered scrape ended last, the full one still has its own values.
+	if got := start(all, status, variables)(); got != nil {
+		t.Errorf("second full scrape: got %v, want none", got)
+	}
+	if got := start(filtered, variables)(); got != nil {
+		t.Errorf("second filtered scrape: got %v, want none", got)
+	}
+}
@@ -13,5 +13,38 @@
 
 package collector
 
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
 // Subsystem.
 const performanceSchema = "perf_schema"
+
+// Tunable flags.
+var (
+	perfSchemaLimit = kingpin.Flag(
+		"collect.perf_schema.limit",
+		"Maximum number of rows collected from large performance_schema summary tables, ordered by their primary counter, 0 for no limit",
+	).Default("0").Int()
+)
+
+// perfSchemaLimitClause returns the LIMIT clause for a summary table query.
+// A positive per-collector limit takes precedence over --collect.perf_schema.limit,
+// otherwise the shared limit applies. Neither limits the rows when it is 0.
+func perfSchemaLimitClause(override int) string {
+	limit := *perfSchemaLimit
+	if override > 0 {
+		limit = override
+	}
+	return limitClause(limit)
+}
+
+// limitClause returns the LIMIT clause for limit, or none if it is not positive.
+func limitClause(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("LIMIT %d", limit)
+}
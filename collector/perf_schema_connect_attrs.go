@@ -0,0 +1,102 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.session_connect_attrs`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Connections of clients that don't send a program_name attribute have no
+// row in session_connect_attrs, hence the join from the foreground threads.
+const perfConnectAttrsQuery = `
+	SELECT
+	    ifnull(a.ATTR_VALUE, 'unknown') AS PROGRAM_NAME,
+	    COUNT(*) AS CONNECTIONS
+	  FROM performance_schema.threads t
+	  LEFT JOIN performance_schema.session_connect_attrs a
+	    ON a.PROCESSLIST_ID = t.PROCESSLIST_ID AND a.ATTR_NAME = 'program_name'
+	  WHERE t.TYPE = 'FOREGROUND' AND t.PROCESSLIST_ID IS NOT NULL
+	  GROUP BY PROGRAM_NAME
+	  ORDER BY CONNECTIONS DESC
+	  %s
+	`
+
+// Tunable flags.
+var (
+	perfConnectAttrsLimit = kingpin.Flag(
+		"collect.perf_schema.connect_attrs.limit",
+		"Limit the number of client programs collected from performance_schema.session_connect_attrs, 0 for no limit",
+	).Default("10").Int()
+)
+
+// Metric descriptors.
+var (
+	performanceSchemaConnectionsByProgramDesc = newNamespacedDesc(
+		performanceSchema, "connections_by_program",
+		"The number of current connections by the program_name connection attribute of the client, \"unknown\" when not sent.",
+		[]string{"program_name"}, nil,
+	)
+)
+
+// ScrapePerfConnectAttrs collects from `performance_schema.session_connect_attrs`.
+type ScrapePerfConnectAttrs struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfConnectAttrs) Name() string {
+	return performanceSchema + ".connect_attrs"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfConnectAttrs) Help() string {
+	return "Collect the number of connections by client program from performance_schema.session_connect_attrs"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfConnectAttrs) Version() float64 {
+	return 5.6
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfConnectAttrs) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	attrsRows, err := db.QueryContext(ctx, fmt.Sprintf(perfConnectAttrsQuery, limitClause(*perfConnectAttrsLimit)))
+	if err != nil {
+		return err
+	}
+	defer attrsRows.Close()
+
+	var (
+		programName string
+		connections float64
+	)
+	for attrsRows.Next() {
+		if err := attrsRows.Scan(&programName, &connections); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaConnectionsByProgramDesc, prometheus.GaugeValue, connections, labelValue(programName, 0),
+		)
+	}
+	return attrsRows.Err()
+}
+
+// check interface
+var _ Scraper = ScrapePerfConnectAttrs{}
@@ -0,0 +1,120 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.data_locks`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const perfDataLocksQuery = `
+	SELECT
+	    LOCK_TYPE, LOCK_MODE, COUNT(*)
+	  FROM performance_schema.data_locks
+	  GROUP BY LOCK_TYPE, LOCK_MODE
+	`
+
+// Metric descriptors.
+var (
+	performanceSchemaDataLocksDesc = newNamespacedDesc(
+		performanceSchema, "data_locks",
+		"The number of data locks currently held or requested by lock type and mode.",
+		[]string{"lock_type", "lock_mode"}, nil,
+	)
+)
+
+type dataLockKey struct {
+	lockType, lockMode string
+}
+
+// Common lock modes always reported, so graphs have a series even without locks.
+var dataLockDefaultKeys = []dataLockKey{
+	{"TABLE", "IS"},
+	{"TABLE", "IX"},
+	{"TABLE", "S"},
+	{"TABLE", "X"},
+	{"TABLE", "AUTO_INC"},
+	{"RECORD", "S"},
+	{"RECORD", "X"},
+	{"RECORD", "S,REC_NOT_GAP"},
+	{"RECORD", "X,REC_NOT_GAP"},
+	{"RECORD", "S,GAP"},
+	{"RECORD", "X,GAP"},
+	{"RECORD", "X,INSERT_INTENTION"},
+}
+
+// ScrapePerfDataLocks collects from `performance_schema.data_locks`.
+type ScrapePerfDataLocks struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfDataLocks) Name() string {
+	return performanceSchema + ".data_locks"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfDataLocks) Help() string {
+	return "Collect lock counts by type and mode from performance_schema.data_locks"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfDataLocks) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfDataLocks) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	perfDataLocksRows, err := db.QueryContext(ctx, perfDataLocksQuery)
+	if err != nil {
+		return err
+	}
+	defer perfDataLocksRows.Close()
+
+	keys := make([]dataLockKey, len(dataLockDefaultKeys))
+	copy(keys, dataLockDefaultKeys)
+	counts := make(map[dataLockKey]uint64, len(keys))
+	for _, key := range keys {
+		counts[key] = 0
+	}
+
+	var (
+		lockType, lockMode string
+		count              uint64
+	)
+	for perfDataLocksRows.Next() {
+		if err := perfDataLocksRows.Scan(&lockType, &lockMode, &count); err != nil {
+			return err
+		}
+		key := dataLockKey{lockType, lockMode}
+		if _, ok := counts[key]; !ok {
+			keys = append(keys, key)
+		}
+		counts[key] += count
+	}
+
+	for _, key := range keys {
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaDataLocksDesc, prometheus.GaugeValue, float64(counts[key]),
+			key.lockType, key.lockMode,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapePerfDataLocks{}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapePerfDataLocks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"LOCK_TYPE", "LOCK_MODE", "COUNT(*)"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("TABLE", "IX", "3").
+		AddRow("RECORD", "X,REC_NOT_GAP", "5").
+		AddRow("RECORD", "S,GAP,INSERT_INTENTION", "1")
+	mock.ExpectQuery(sanitizeQuery(perfDataLocksQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapePerfDataLocks{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"lock_type": "TABLE", "lock_mode": "IS"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "TABLE", "lock_mode": "IX"}, value: 3, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "TABLE", "lock_mode": "S"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "TABLE", "lock_mode": "X"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "TABLE", "lock_mode": "AUTO_INC"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "S"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "X"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "S,REC_NOT_GAP"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "X,REC_NOT_GAP"}, value: 5, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "S,GAP"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "X,GAP"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "X,INSERT_INTENTION"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"lock_type": "RECORD", "lock_mode": "S,GAP,INSERT_INTENTION"}, value: 1, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
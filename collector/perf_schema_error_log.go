@@ -0,0 +1,137 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.error_log`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const perfErrorLogQuery = `
+	SELECT
+	    PRIO,
+	    ifnull(SUBSYSTEM, '') AS SUBSYSTEM,
+	    COUNT(*) AS ENTRIES,
+	    MAX(LOGGED) AS LAST_LOGGED
+	  FROM performance_schema.error_log
+	  WHERE LOGGED > ?
+	  GROUP BY PRIO, SUBSYSTEM
+	`
+
+// Entries logged after this time are counted on the first scrape.
+const perfErrorLogStart = "1970-01-01 00:00:01.000000"
+
+// Metric descriptors.
+var (
+	performanceSchemaErrorLogEntriesDesc = newNamespacedDesc(
+		performanceSchema, "error_log_entries_total",
+		"Total number of error log entries by priority and subsystem, counted since the exporter started.",
+		[]string{"prio", "subsystem"}, nil,
+	)
+)
+
+// ScrapePerfErrorLog collects from `performance_schema.error_log`.
+// It is stateful: only the entries logged since the previous scrape are
+// read and added to the counters, so it must be used as a pointer.
+type ScrapePerfErrorLog struct {
+	mu         sync.Mutex
+	lastLogged string
+	entries    map[[2]string]uint64
+}
+
+// Name of the Scraper. Should be unique.
+func (*ScrapePerfErrorLog) Name() string {
+	return performanceSchema + ".error_log"
+}
+
+// Help describes the role of the Scraper.
+func (*ScrapePerfErrorLog) Help() string {
+	return "Collect the number of error log entries by priority and subsystem from performance_schema.error_log"
+}
+
+// Version of MySQL from which scraper is available.
+func (*ScrapePerfErrorLog) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (s *ScrapePerfErrorLog) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries == nil {
+		s.entries = map[[2]string]uint64{}
+		s.lastLogged = perfErrorLogStart
+	}
+
+	errorLogRows, err := db.QueryContext(ctx, perfErrorLogQuery, s.lastLogged)
+	if err != nil {
+		return err
+	}
+	defer errorLogRows.Close()
+
+	// Apply the new entries only once all of them were read, so that a
+	// failed scrape is retried from the same point.
+	var (
+		prio, subsystem, logged string
+		count                   uint64
+		lastLogged              = s.lastLogged
+		newEntries              = map[[2]string]uint64{}
+	)
+	for errorLogRows.Next() {
+		if err := errorLogRows.Scan(&prio, &subsystem, &count, &logged); err != nil {
+			return err
+		}
+		newEntries[[2]string{prio, subsystem}] += count
+		// LOGGED has a fixed width format, so the latest also sorts last.
+		if logged > lastLogged {
+			lastLogged = logged
+		}
+	}
+	if err := errorLogRows.Err(); err != nil {
+		return err
+	}
+	for key, count := range newEntries {
+		s.entries[key] += count
+	}
+	s.lastLogged = lastLogged
+
+	keys := make([][2]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaErrorLogEntriesDesc, prometheus.CounterValue, float64(s.entries[key]),
+			key[0], key[1],
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = &ScrapePerfErrorLog{}
@@ -0,0 +1,79 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapePerfErrorLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"PRIO", "SUBSYSTEM", "ENTRIES", "LAST_LOGGED"}
+	mock.ExpectQuery(sanitizeQuery(perfErrorLogQuery)).
+		WithArgs(perfErrorLogStart).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("System", "Server", "4", "2021-10-01 12:00:00.000100").
+			AddRow("Warning", "InnoDB", "2", "2021-10-01 12:05:00.000000"))
+	// The second scrape only reads the entries logged since the first one.
+	mock.ExpectQuery(sanitizeQuery(perfErrorLogQuery)).
+		WithArgs("2021-10-01 12:05:00.000000").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("Error", "Repl", "1", "2021-10-01 12:06:00.000000").
+			AddRow("Warning", "InnoDB", "3", "2021-10-01 12:07:00.000000"))
+
+	scraper := &ScrapePerfErrorLog{}
+	scrape := func() []MetricResult {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			if err := scraper.Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+				t.Errorf("error calling function on test: %s", err)
+			}
+			close(ch)
+		}()
+		var got []MetricResult
+		for m := range ch {
+			got = append(got, readMetric(m))
+		}
+		return got
+	}
+
+	convey.Convey("Metrics comparison", t, func() {
+		convey.So(scrape(), convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{"prio": "System", "subsystem": "Server"}, value: 4, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"prio": "Warning", "subsystem": "InnoDB"}, value: 2, metricType: dto.MetricType_COUNTER},
+		})
+		convey.So(scrape(), convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{"prio": "Error", "subsystem": "Repl"}, value: 1, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"prio": "System", "subsystem": "Server"}, value: 4, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"prio": "Warning", "subsystem": "InnoDB"}, value: 5, metricType: dto.MetricType_COUNTER},
+		})
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.events_transactions_summary_global_by_event_name`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const perfTransactionsConsumerQuery = `
+	SELECT
+	    ENABLED
+	  FROM performance_schema.setup_consumers
+	  WHERE NAME = 'events_transactions_current'
+	`
+
+const perfEventsTransactionsQuery = `
+	SELECT
+	    EVENT_NAME, COUNT_STAR, SUM_TIMER_WAIT
+	  FROM performance_schema.events_transactions_summary_global_by_event_name
+	`
+
+// Metric descriptors.
+var (
+	performanceSchemaTransactionsDesc = newNamespacedDesc(
+		performanceSchema, "transactions_total",
+		"The total number of transactions by event name.",
+		[]string{"name"}, nil,
+	)
+	performanceSchemaTransactionsTimeDesc = newNamespacedDesc(
+		performanceSchema, "transactions_seconds_total",
+		"The total time of transactions by event name.",
+		[]string{"name"}, nil,
+	)
+)
+
+// ScrapePerfEventsTransactions collects from `performance_schema.events_transactions_summary_global_by_event_name`.
+type ScrapePerfEventsTransactions struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfEventsTransactions) Name() string {
+	return performanceSchema + ".transactions"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfEventsTransactions) Help() string {
+	return "Collect metrics from performance_schema.events_transactions_summary_global_by_event_name"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfEventsTransactions) Version() float64 {
+	return 5.7
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfEventsTransactions) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var consumerEnabled string
+	err := db.QueryRowContext(ctx, perfTransactionsConsumerQuery).Scan(&consumerEnabled)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if consumerEnabled != "YES" {
+		level.Debug(logger).Log("msg", "performance_schema events_transactions_current consumer is disabled, skipping")
+		return nil
+	}
+
+	perfEventsTransactionsRows, err := db.QueryContext(ctx, perfEventsTransactionsQuery)
+	if err != nil {
+		return err
+	}
+	defer perfEventsTransactionsRows.Close()
+
+	var (
+		eventName string
+		count     uint64
+		timeWait  uint64
+	)
+	for perfEventsTransactionsRows.Next() {
+		if err := perfEventsTransactionsRows.Scan(&eventName, &count, &timeWait); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaTransactionsDesc, prometheus.CounterValue, float64(count),
+			eventName,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaTransactionsTimeDesc, prometheus.CounterValue, float64(timeWait)/picoSeconds,
+			eventName,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapePerfEventsTransactions{}
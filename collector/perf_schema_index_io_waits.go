@@ -18,9 +18,11 @@ package collector
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const perfIndexIOWaitsQuery = `
@@ -29,17 +31,27 @@ const perfIndexIOWaitsQuery = `
 	    SUM_TIMER_FETCH, SUM_TIMER_INSERT, SUM_TIMER_UPDATE, SUM_TIMER_DELETE
 	  FROM performance_schema.table_io_waits_summary_by_index_usage
 	  WHERE OBJECT_SCHEMA NOT IN ('mysql', 'performance_schema')
+	  ORDER BY COUNT_STAR DESC
+	  %s
 	`
 
+// Tunable flags.
+var (
+	perfIndexIOWaitsLimit = kingpin.Flag(
+		"collect.perf_schema.indexiowaits.limit",
+		"Limit the number of rows collected from performance_schema.table_io_waits_summary_by_index_usage, 0 for --collect.perf_schema.limit",
+	).Default("0").Int()
+)
+
 // Metric descriptors.
 var (
-	performanceSchemaIndexWaitsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "index_io_waits_total"),
+	performanceSchemaIndexWaitsDesc = newNamespacedDesc(
+		performanceSchema, "index_io_waits_total",
 		"The total number of index I/O wait events for each index and operation.",
 		[]string{"schema", "name", "index", "operation"}, nil,
 	)
-	performanceSchemaIndexWaitsTimeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "index_io_waits_seconds_total"),
+	performanceSchemaIndexWaitsTimeDesc = newNamespacedDesc(
+		performanceSchema, "index_io_waits_seconds_total",
 		"The total time of index I/O wait events for each index and operation.",
 		[]string{"schema", "name", "index", "operation"}, nil,
 	)
@@ -65,7 +77,7 @@ func (ScrapePerfIndexIOWaits) Version() float64 {
 
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfIndexIOWaits) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
-	perfSchemaIndexWaitsRows, err := db.QueryContext(ctx, perfIndexIOWaitsQuery)
+	perfSchemaIndexWaitsRows, err := db.QueryContext(ctx, fmt.Sprintf(perfIndexIOWaitsQuery, perfSchemaLimitClause(*perfIndexIOWaitsLimit)))
 	if err != nil {
 		return err
 	}
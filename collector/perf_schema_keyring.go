@@ -0,0 +1,132 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.keyring_keys` and `performance_schema.keyring_component_status`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ER_NO_SUCH_TABLE, returned by servers older than the keyring tables.
+const noSuchTableError = 1146
+
+const perfKeyringKeysQuery = `
+	SELECT COUNT(*)
+	  FROM performance_schema.keyring_keys
+	`
+
+// keyring_component_status is empty unless a keyring component is loaded.
+const perfKeyringComponentStatusQuery = `
+	SELECT
+	    STATUS_KEY,
+	    STATUS_VALUE
+	  FROM performance_schema.keyring_component_status
+	  WHERE STATUS_KEY IN ('Component_name', 'Component_status')
+	`
+
+// Metric descriptors.
+var (
+	performanceSchemaKeyringKeysDesc = newNamespacedDesc(
+		performanceSchema, "keyring_keys_count",
+		"The number of keys in the keyring.",
+		nil, nil,
+	)
+	performanceSchemaKeyringComponentStatusDesc = newNamespacedDesc(
+		performanceSchema, "keyring_component_status",
+		"Whether the loaded keyring component is active (1) or disabled (0). Encrypted tablespaces can't be opened without an active keyring.",
+		[]string{"component"}, nil,
+	)
+)
+
+// ScrapePerfKeyring collects from `performance_schema.keyring_keys` and `performance_schema.keyring_component_status`.
+type ScrapePerfKeyring struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfKeyring) Name() string {
+	return performanceSchema + ".keyring"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfKeyring) Help() string {
+	return "Collect the number of keyring keys and the keyring component status from performance_schema"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfKeyring) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfKeyring) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	var keys uint64
+	if err := db.QueryRowContext(ctx, perfKeyringKeysQuery).Scan(&keys); err != nil {
+		if isNoSuchTable(err) {
+			level.Debug(logger).Log("msg", "Skipping the keyring, performance_schema.keyring_keys is not supported", "err", err)
+			return nil
+		}
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		performanceSchemaKeyringKeysDesc, prometheus.GaugeValue, float64(keys),
+	)
+
+	statusRows, err := db.QueryContext(ctx, perfKeyringComponentStatusQuery)
+	if err != nil {
+		if isNoSuchTable(err) {
+			level.Debug(logger).Log("msg", "Skipping the keyring component status, performance_schema.keyring_component_status is not supported", "err", err)
+			return nil
+		}
+		return err
+	}
+	defer statusRows.Close()
+
+	var key, value string
+	status := map[string]string{}
+	for statusRows.Next() {
+		if err := statusRows.Scan(&key, &value); err != nil {
+			return err
+		}
+		status[key] = value
+	}
+	if err := statusRows.Err(); err != nil {
+		return err
+	}
+	// Keyring plugins don't appear in keyring_component_status.
+	if name, ok := status["Component_name"]; ok {
+		var active float64
+		if status["Component_status"] == "Active" {
+			active = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaKeyringComponentStatusDesc, prometheus.GaugeValue, active, name,
+		)
+	}
+	return nil
+}
+
+func isNoSuchTable(err error) bool {
+	var mysqlErr *gomysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == noSuchTableError
+}
+
+// check interface
+var _ Scraper = ScrapePerfKeyring{}
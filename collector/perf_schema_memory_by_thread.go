@@ -0,0 +1,102 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.memory_summary_by_thread_by_event_name`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// CURRENT_NUMBER_OF_BYTES_USED can be negative for an event name when the
+// memory was freed by another thread than the one allocating it.
+const perfMemoryByThreadQuery = `
+	SELECT
+	    m.THREAD_ID,
+	    ifnull(t.PROCESSLIST_USER, '') AS PROCESSLIST_USER,
+	    SUM(m.CURRENT_NUMBER_OF_BYTES_USED) AS CURRENT_NUMBER_OF_BYTES_USED
+	  FROM performance_schema.memory_summary_by_thread_by_event_name m
+	  JOIN performance_schema.threads t ON t.THREAD_ID = m.THREAD_ID
+	  GROUP BY m.THREAD_ID, t.PROCESSLIST_USER
+	  ORDER BY CURRENT_NUMBER_OF_BYTES_USED DESC
+	  %s
+	`
+
+// Tunable flags.
+var (
+	perfMemoryByThreadLimit = kingpin.Flag(
+		"collect.perf_schema.memory_by_thread.limit",
+		"Limit the number of threads collected from performance_schema.memory_summary_by_thread_by_event_name, 0 for no limit",
+	).Default("10").Int()
+)
+
+// Metric descriptors.
+var (
+	performanceSchemaThreadMemoryDesc = newNamespacedDesc(
+		performanceSchema, "thread_memory_bytes",
+		"The memory currently used by the top threads by memory usage.",
+		[]string{"thread_id", "processlist_user"}, nil,
+	)
+)
+
+// ScrapePerfMemoryByThread collects from `performance_schema.memory_summary_by_thread_by_event_name`.
+type ScrapePerfMemoryByThread struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfMemoryByThread) Name() string {
+	return performanceSchema + ".memory_by_thread"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfMemoryByThread) Help() string {
+	return "Collect the top threads by memory usage from performance_schema.memory_summary_by_thread_by_event_name"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfMemoryByThread) Version() float64 {
+	return 5.7
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfMemoryByThread) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	memoryRows, err := db.QueryContext(ctx, fmt.Sprintf(perfMemoryByThreadQuery, limitClause(*perfMemoryByThreadLimit)))
+	if err != nil {
+		return err
+	}
+	defer memoryRows.Close()
+
+	var (
+		threadID, user string
+		bytes          float64
+	)
+	for memoryRows.Next() {
+		if err := memoryRows.Scan(&threadID, &user, &bytes); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaThreadMemoryDesc, prometheus.GaugeValue, bytes,
+			threadID, user,
+		)
+	}
+	return memoryRows.Err()
+}
+
+// check interface
+var _ Scraper = ScrapePerfMemoryByThread{}
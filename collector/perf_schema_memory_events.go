@@ -43,18 +43,18 @@ var (
 
 // Metric descriptors.
 var (
-	performanceSchemaMemoryBytesAllocDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "memory_events_alloc_bytes_total"),
+	performanceSchemaMemoryBytesAllocDesc = newNamespacedDesc(
+		performanceSchema, "memory_events_alloc_bytes_total",
 		"The total number of bytes allocated by events.",
 		[]string{"event_name"}, nil,
 	)
-	performanceSchemaMemoryBytesFreeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "memory_events_free_bytes_total"),
+	performanceSchemaMemoryBytesFreeDesc = newNamespacedDesc(
+		performanceSchema, "memory_events_free_bytes_total",
 		"The total number of bytes freed by events.",
 		[]string{"event_name"}, nil,
 	)
-	perforanceSchemaMemoryUsedBytesDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "memory_events_used_bytes"),
+	perforanceSchemaMemoryUsedBytesDesc = newNamespacedDesc(
+		performanceSchema, "memory_events_used_bytes",
 		"The number of bytes currently allocated by events.",
 		[]string{"event_name"}, nil,
 	)
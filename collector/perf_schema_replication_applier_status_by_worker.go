@@ -35,48 +35,60 @@ const perfReplicationApplierStatsByWorkerQuery = `
 	  	APPLYING_TRANSACTION_START_APPLY_TIMESTAMP
     FROM performance_schema.replication_applier_status_by_worker
 	`
+
+// The lag of a channel is the age of the oldest transaction its workers are
+// applying, computed by the server as the timestamps are in its time zone.
+const perfReplicationApplierLagQuery = `
+	SELECT
+	    CHANNEL_NAME,
+	    MAX(IF(APPLYING_TRANSACTION_ORIGINAL_COMMIT_TIMESTAMP = 0, 0,
+	        TIMESTAMPDIFF(MICROSECOND, APPLYING_TRANSACTION_ORIGINAL_COMMIT_TIMESTAMP, NOW(6)))) / 1000000 AS LAG
+	  FROM performance_schema.replication_applier_status_by_worker
+	  GROUP BY CHANNEL_NAME
+	`
+
 const timeLayout = "2006-01-02 15:04:05.000000"
 
 // Metric descriptors.
 var (
-	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionOriginalCommitSecondDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "last_applied_transaction_original_commit_timestamp_seconds"),
+	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionOriginalCommitSecondDesc = newNamespacedDesc(
+		performanceSchema, "last_applied_transaction_original_commit_timestamp_seconds",
 		"A timestamp shows when the last transaction applied by this worker was committed on the original master.",
 		[]string{"channel_name", "member_id"}, nil,
 	)
 
-	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionImmediateCommitSecondDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "last_applied_transaction_immediate_commit_timestamp_seconds"),
+	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionImmediateCommitSecondDesc = newNamespacedDesc(
+		performanceSchema, "last_applied_transaction_immediate_commit_timestamp_seconds",
 		"A timestamp shows when the last transaction applied by this worker was committed on the immediate master.",
 		[]string{"channel_name", "member_id"}, nil,
 	)
 
-	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionStartApplySecondDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "last_applied_transaction_start_apply_timestamp_seconds"),
+	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionStartApplySecondDesc = newNamespacedDesc(
+		performanceSchema, "last_applied_transaction_start_apply_timestamp_seconds",
 		"A timestamp shows when this worker started applying the last applied transaction.",
 		[]string{"channel_name", "member_id"}, nil,
 	)
 
-	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionEndApplySecondDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "last_applied_transaction_end_apply_timestamp_seconds"),
+	performanceSchemaReplicationApplierStatsByWorkerLastAppliedTransactionEndApplySecondDesc = newNamespacedDesc(
+		performanceSchema, "last_applied_transaction_end_apply_timestamp_seconds",
 		"A shows when this worker finished applying the last applied transaction.",
 		[]string{"channel_name", "member_id"}, nil,
 	)
 
-	performanceSchemaReplicationApplierStatsByWorkerApplyingTransactionOriginalCommitSecondDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "applying_transaction_original_commit_timestamp_seconds"),
+	performanceSchemaReplicationApplierStatsByWorkerApplyingTransactionOriginalCommitSecondDesc = newNamespacedDesc(
+		performanceSchema, "applying_transaction_original_commit_timestamp_seconds",
 		"A timestamp that shows when the transaction this worker is currently applying was committed on the original master.",
 		[]string{"channel_name", "member_id"}, nil,
 	)
 
-	performanceSchemaReplicationApplierStatsByWorkerApplyingTransactionImmediateCommitSecondDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "applying_transaction_immediate_commit_timestamp_seconds"),
+	performanceSchemaReplicationApplierStatsByWorkerApplyingTransactionImmediateCommitSecondDesc = newNamespacedDesc(
+		performanceSchema, "applying_transaction_immediate_commit_timestamp_seconds",
 		"A timestamp shows when the transaction this worker is currently applying was committed on the immediate master.",
 		[]string{"channel_name", "member_id"}, nil,
 	)
 
-	performanceSchemaReplicationApplierStatsByWorkerApplyingTransactionStartApplySecondDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "applying_transaction_start_apply_timestamp_seconds"),
+	performanceSchemaReplicationApplierStatsByWorkerApplyingTransactionStartApplySecondDesc = newNamespacedDesc(
+		performanceSchema, "applying_transaction_start_apply_timestamp_seconds",
 		"A timestamp shows when this worker started its first attempt to apply the transaction that is currently being applied.",
 		[]string{"channel_name", "member_id"}, nil,
 	)
@@ -230,7 +242,27 @@ func (ScrapePerfReplicationApplierStatsByWorker) Scrape(ctx context.Context, db
 			prometheus.GaugeValue, applyingTransactionStartApplySeconds, channelName, workerId,
 		)
 	}
-	return nil
+	if *replicationLagSource != "perf_schema" {
+		return nil
+	}
+	perfReplicationApplierStatsByWorkerRows.Close()
+
+	lagRows, err := db.QueryContext(ctx, perfReplicationApplierLagQuery)
+	if err != nil {
+		return err
+	}
+	defer lagRows.Close()
+
+	var lag float64
+	for lagRows.Next() {
+		if err := lagRows.Scan(&channelName, &lag); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			replicationLagDesc, prometheus.GaugeValue, lag, "perf_schema", channelName,
+		)
+	}
+	return lagRows.Err()
 }
 
 // check interface
@@ -23,6 +23,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 func TestScrapePerfReplicationApplierStatsByWorker(t *testing.T) {
@@ -88,3 +89,68 @@ func TestScrapePerfReplicationApplierStatsByWorker(t *testing.T) {
 		t.Errorf("there were unfulfilled exceptions: %s", err)
 	}
 }
+
+func TestScrapePerfReplicationApplierStatsByWorkerLag(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.replication.lag-source=perf_schema"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{
+		"CHANNEL_NAME",
+		"WORKER_ID",
+		"LAST_APPLIED_TRANSACTION_ORIGINAL_COMMIT_TIMESTAMP",
+		"LAST_APPLIED_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP",
+		"LAST_APPLIED_TRANSACTION_START_APPLY_TIMESTAMP",
+		"LAST_APPLIED_TRANSACTION_END_APPLY_TIMESTAMP",
+		"APPLYING_TRANSACTION_ORIGINAL_COMMIT_TIMESTAMP",
+		"APPLYING_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP",
+		"APPLYING_TRANSACTION_START_APPLY_TIMESTAMP",
+	}
+	timeZero := "0000-00-00 00:00:00.000000"
+	rows := sqlmock.NewRows(columns).
+		AddRow("dummy_0", "0", timeZero, timeZero, timeZero, timeZero, timeZero, timeZero, timeZero)
+	mock.ExpectQuery(sanitizeQuery(perfReplicationApplierStatsByWorkerQuery)).WillReturnRows(rows)
+	lagRows := sqlmock.NewRows([]string{"CHANNEL_NAME", "LAG"}).
+		AddRow("dummy_0", "0.0000").
+		AddRow("dummy_1", "12.5000")
+	mock.ExpectQuery(sanitizeQuery(perfReplicationApplierLagQuery)).WillReturnRows(lagRows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapePerfReplicationApplierStatsByWorker{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"source": "perf_schema", "channel_name": "dummy_0"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"source": "perf_schema", "channel_name": "dummy_1"}, value: 12.5, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		// Skip the timestamps of the worker.
+		for i := 0; i < 7; i++ {
+			<-ch
+		}
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"mysql_replication_lag_seconds"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
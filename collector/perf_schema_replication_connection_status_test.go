@@ -0,0 +1,77 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapePerfReplicationConnectionStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"CHANNEL_NAME", "LAST_HEARTBEAT_TIMESTAMP", "RECEIVED_TRANSACTION_SET"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("", "2021-10-01 12:00:00.500000", "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-100:105,\n4f22ab58-71ca-11e1-9e33-c80aa9429562:1-3").
+		AddRow("backup", "0000-00-00 00:00:00.000000", "").
+		AddRow("legacy", "2021-10-01 12:00:00", "")
+	mock.ExpectQuery(sanitizeQuery(perfReplicationConnectionStatusQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapePerfReplicationConnectionStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"channel_name": ""}, value: 1633089600.5, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"channel_name": ""}, value: 104, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"channel_name": "backup"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"channel_name": "backup"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"channel_name": "legacy"}, value: 1633089600, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"channel_name": "legacy"}, value: 0, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestGTIDSetCount(t *testing.T) {
+	convey.Convey("GTID set counting", t, func() {
+		convey.So(gtidSetCount(""), convey.ShouldEqual, 0)
+		convey.So(gtidSetCount("3e11fa47-71ca-11e1-9e33-c80aa9429562:7"), convey.ShouldEqual, 1)
+		convey.So(gtidSetCount("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:11-12"), convey.ShouldEqual, 7)
+		convey.So(gtidSetCount("3e11fa47-71ca-11e1-9e33-c80aa9429562:tag:1-2"), convey.ShouldEqual, 2)
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.socket_summary_by_event_name`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The summary by event name aggregates socket_summary_by_instance, including
+// the sockets already closed, so its values never decrease.
+const perfSocketSummaryQuery = `
+	SELECT
+	    EVENT_NAME,
+	    COUNT_READ, COUNT_WRITE, COUNT_MISC,
+	    SUM_NUMBER_OF_BYTES_READ, SUM_NUMBER_OF_BYTES_WRITE
+	  FROM performance_schema.socket_summary_by_event_name
+	`
+
+// Metric descriptors.
+var (
+	performanceSchemaSocketOperationsDesc = newNamespacedDesc(
+		performanceSchema, "socket_operations_total",
+		"The total number of socket operations by socket type and operation.",
+		[]string{"event_name", "operation"}, nil,
+	)
+	performanceSchemaSocketBytesDesc = newNamespacedDesc(
+		performanceSchema, "socket_bytes_total",
+		"The total number of bytes read from and written to sockets by socket type.",
+		[]string{"event_name", "operation"}, nil,
+	)
+)
+
+// ScrapePerfSocketSummary collects from `performance_schema.socket_summary_by_event_name`.
+type ScrapePerfSocketSummary struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfSocketSummary) Name() string {
+	return performanceSchema + ".socket_summary"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfSocketSummary) Help() string {
+	return "Collect socket I/O by socket type from performance_schema.socket_summary_by_event_name"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfSocketSummary) Version() float64 {
+	return 5.6
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfSocketSummary) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	socketRows, err := db.QueryContext(ctx, perfSocketSummaryQuery)
+	if err != nil {
+		return err
+	}
+	defer socketRows.Close()
+
+	var (
+		eventName                        string
+		countRead, countWrite, countMisc uint64
+		bytesRead, bytesWrite            uint64
+	)
+	for socketRows.Next() {
+		if err := socketRows.Scan(
+			&eventName, &countRead, &countWrite, &countMisc, &bytesRead, &bytesWrite,
+		); err != nil {
+			return err
+		}
+		// wait/io/socket/sql/client_connection -> client_connection
+		eventName = strings.TrimPrefix(eventName, "wait/io/socket/sql/")
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaSocketOperationsDesc, prometheus.CounterValue, float64(countRead),
+			eventName, "read",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaSocketOperationsDesc, prometheus.CounterValue, float64(countWrite),
+			eventName, "write",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaSocketOperationsDesc, prometheus.CounterValue, float64(countMisc),
+			eventName, "misc",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaSocketBytesDesc, prometheus.CounterValue, float64(bytesRead),
+			eventName, "read",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaSocketBytesDesc, prometheus.CounterValue, float64(bytesWrite),
+			eventName, "write",
+		)
+	}
+	return socketRows.Err()
+}
+
+// check interface
+var _ Scraper = ScrapePerfSocketSummary{}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.status_by_thread`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const perfStatusByThreadQuery = `
+	SELECT
+	    s.THREAD_ID,
+	    ifnull(t.PROCESSLIST_USER, '') AS PROCESSLIST_USER,
+	    ifnull(t.PROCESSLIST_HOST, '') AS PROCESSLIST_HOST,
+	    s.VARIABLE_VALUE
+	  FROM performance_schema.status_by_thread s
+	  JOIN performance_schema.threads t ON t.THREAD_ID = s.THREAD_ID
+	  WHERE s.VARIABLE_NAME = ?
+	  ORDER BY CAST(s.VARIABLE_VALUE AS UNSIGNED) DESC
+	  %s
+	`
+
+// Tunable flags.
+var (
+	perfStatusByThreadVariable = kingpin.Flag(
+		"collect.perf_schema.status_by_thread.variable",
+		"Status variable used to rank threads from performance_schema.status_by_thread",
+	).Default("Bytes_sent").String()
+	perfStatusByThreadLimit = kingpin.Flag(
+		"collect.perf_schema.status_by_thread.limit",
+		"Limit the number of threads collected from performance_schema.status_by_thread, 0 for no limit",
+	).Default("10").Int()
+)
+
+// Metric descriptors.
+var (
+	performanceSchemaThreadStatusDesc = newNamespacedDesc(
+		performanceSchema, "thread_status",
+		"The value of the status variable for the top threads by that variable.",
+		[]string{"thread_id", "processlist_user", "processlist_host", "variable"}, nil,
+	)
+)
+
+// ScrapePerfStatusByThread collects from `performance_schema.status_by_thread`.
+type ScrapePerfStatusByThread struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfStatusByThread) Name() string {
+	return performanceSchema + ".status_by_thread"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfStatusByThread) Help() string {
+	return "Collect the top threads by a status variable from performance_schema.status_by_thread"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfStatusByThread) Version() float64 {
+	return 5.7
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfStatusByThread) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	statusRows, err := db.QueryContext(ctx, fmt.Sprintf(perfStatusByThreadQuery, limitClause(*perfStatusByThreadLimit)), *perfStatusByThreadVariable)
+	if err != nil {
+		return err
+	}
+	defer statusRows.Close()
+
+	var (
+		threadID   string
+		user, host string
+		value      sql.RawBytes
+	)
+	for statusRows.Next() {
+		if err := statusRows.Scan(&threadID, &user, &host, &value); err != nil {
+			return err
+		}
+		floatVal, ok := parseStatus(value)
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaThreadStatusDesc, prometheus.GaugeValue, floatVal,
+			threadID, user, host, *perfStatusByThreadVariable,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapePerfStatusByThread{}
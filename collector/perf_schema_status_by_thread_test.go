@@ -0,0 +1,76 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestScrapePerfStatusByThread(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{
+		"--collect.perf_schema.status_by_thread.variable", "Handler_read_rnd_next",
+		"--collect.perf_schema.status_by_thread.limit", "2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"THREAD_ID", "PROCESSLIST_USER", "PROCESSLIST_HOST", "VARIABLE_VALUE"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("48", "app", "10.0.0.1", "1500000").
+		AddRow("52", "report", "10.0.0.2", "90000")
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(perfStatusByThreadQuery, "LIMIT 2"))).
+		WithArgs("Handler_read_rnd_next").
+		WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapePerfStatusByThread{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []MetricResult{
+		{labels: labelMap{"thread_id": "48", "processlist_user": "app", "processlist_host": "10.0.0.1", "variable": "Handler_read_rnd_next"}, value: 1500000, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"thread_id": "52", "processlist_user": "report", "processlist_host": "10.0.0.2", "variable": "Handler_read_rnd_next"}, value: 90000, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -18,9 +18,11 @@ package collector
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const perfTableIOWaitsQuery = `
@@ -30,17 +32,27 @@ const perfTableIOWaitsQuery = `
 	    SUM_TIMER_FETCH, SUM_TIMER_INSERT, SUM_TIMER_UPDATE, SUM_TIMER_DELETE
 	  FROM performance_schema.table_io_waits_summary_by_table
 	  WHERE OBJECT_SCHEMA NOT IN ('mysql', 'performance_schema')
+	  ORDER BY COUNT_STAR DESC
+	  %s
 	`
 
+// Tunable flags.
+var (
+	perfTableIOWaitsLimit = kingpin.Flag(
+		"collect.perf_schema.tableiowaits.limit",
+		"Limit the number of rows collected from performance_schema.table_io_waits_summary_by_table, 0 for --collect.perf_schema.limit",
+	).Default("0").Int()
+)
+
 // Metric descriptors.
 var (
-	performanceSchemaTableWaitsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "table_io_waits_total"),
+	performanceSchemaTableWaitsDesc = newNamespacedDesc(
+		performanceSchema, "table_io_waits_total",
 		"The total number of table I/O wait events for each table and operation.",
 		[]string{"schema", "name", "operation"}, nil,
 	)
-	performanceSchemaTableWaitsTimeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "table_io_waits_seconds_total"),
+	performanceSchemaTableWaitsTimeDesc = newNamespacedDesc(
+		performanceSchema, "table_io_waits_seconds_total",
 		"The total time of table I/O wait events for each table and operation.",
 		[]string{"schema", "name", "operation"}, nil,
 	)
@@ -66,7 +78,7 @@ func (ScrapePerfTableIOWaits) Version() float64 {
 
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfTableIOWaits) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
-	perfSchemaTableWaitsRows, err := db.QueryContext(ctx, perfTableIOWaitsQuery)
+	perfSchemaTableWaitsRows, err := db.QueryContext(ctx, fmt.Sprintf(perfTableIOWaitsQuery, perfSchemaLimitClause(*perfTableIOWaitsLimit)))
 	if err != nil {
 		return err
 	}
@@ -18,9 +18,11 @@ package collector
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const perfTableLockWaitsQuery = `
@@ -49,27 +51,37 @@ const perfTableLockWaitsQuery = `
 	    SUM_TIMER_WRITE_EXTERNAL
 	  FROM performance_schema.table_lock_waits_summary_by_table
 	  WHERE OBJECT_SCHEMA NOT IN ('mysql', 'performance_schema', 'information_schema')
+	  ORDER BY COUNT_STAR DESC
+	  %s
 	`
 
+// Tunable flags.
+var (
+	perfTableLockWaitsLimit = kingpin.Flag(
+		"collect.perf_schema.tablelocks.limit",
+		"Limit the number of rows collected from performance_schema.table_lock_waits_summary_by_table, 0 for --collect.perf_schema.limit",
+	).Default("0").Int()
+)
+
 // Metric descriptors.
 var (
-	performanceSchemaSQLTableLockWaitsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "sql_lock_waits_total"),
+	performanceSchemaSQLTableLockWaitsDesc = newNamespacedDesc(
+		performanceSchema, "sql_lock_waits_total",
 		"The total number of SQL lock wait events for each table and operation.",
 		[]string{"schema", "name", "operation"}, nil,
 	)
-	performanceSchemaExternalTableLockWaitsDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "external_lock_waits_total"),
+	performanceSchemaExternalTableLockWaitsDesc = newNamespacedDesc(
+		performanceSchema, "external_lock_waits_total",
 		"The total number of external lock wait events for each table and operation.",
 		[]string{"schema", "name", "operation"}, nil,
 	)
-	performanceSchemaSQLTableLockWaitsTimeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "sql_lock_waits_seconds_total"),
+	performanceSchemaSQLTableLockWaitsTimeDesc = newNamespacedDesc(
+		performanceSchema, "sql_lock_waits_seconds_total",
 		"The total time of SQL lock wait events for each table and operation.",
 		[]string{"schema", "name", "operation"}, nil,
 	)
-	performanceSchemaExternalTableLockWaitsTimeDesc = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, performanceSchema, "external_lock_waits_seconds_total"),
+	performanceSchemaExternalTableLockWaitsTimeDesc = newNamespacedDesc(
+		performanceSchema, "external_lock_waits_seconds_total",
 		"The total time of external lock wait events for each table and operation.",
 		[]string{"schema", "name", "operation"}, nil,
 	)
@@ -95,7 +107,7 @@ func (ScrapePerfTableLockWaits) Version() float64 {
 
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfTableLockWaits) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
-	perfSchemaTableLockWaitsRows, err := db.QueryContext(ctx, perfTableLockWaitsQuery)
+	perfSchemaTableLockWaitsRows, err := db.QueryContext(ctx, fmt.Sprintf(perfTableLockWaitsQuery, perfSchemaLimitClause(*perfTableLockWaitsLimit)))
 	if err != nil {
 		return err
 	}
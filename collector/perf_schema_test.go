@@ -0,0 +1,62 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"testing"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestPerfSchemaLimitClause(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.perf_schema.limit=100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	for override, want := range map[int]string{
+		0:  "LIMIT 100",
+		5:  "LIMIT 5",
+		-1: "LIMIT 100",
+	} {
+		if got := perfSchemaLimitClause(override); got != want {
+			t.Errorf("perfSchemaLimitClause(%d) = %q, want %q", override, got, want)
+		}
+	}
+
+	if _, err := kingpin.CommandLine.Parse([]string{"--collect.perf_schema.limit=0"}); err != nil {
+		t.Fatal(err)
+	}
+	for override, want := range map[int]string{
+		0: "",
+		5: "LIMIT 5",
+	} {
+		if got := perfSchemaLimitClause(override); got != want {
+			t.Errorf("perfSchemaLimitClause(%d) with no shared limit = %q, want %q", override, got, want)
+		}
+	}
+}
+
+func TestLimitClause(t *testing.T) {
+	for limit, want := range map[int]string{
+		10: "LIMIT 10",
+		0:  "",
+		-5: "",
+	} {
+		if got := limitClause(limit); got != want {
+			t.Errorf("limitClause(%d) = %q, want %q", limit, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.tls_channel_status`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The Server_not_after property holds the expiry date of the certificate
+// currently used by the channel, in the OpenSSL format.
+const perfTLSChannelStatusQuery = `
+	SELECT
+	    CHANNEL,
+	    VALUE
+	  FROM performance_schema.tls_channel_status
+	  WHERE PROPERTY = 'Server_not_after'
+	`
+
+const tlsCertTimeLayout = "Jan _2 15:04:05 2006 MST"
+
+// Metric descriptors.
+var (
+	performanceSchemaTLSCertExpiryDesc = newNamespacedDesc(
+		performanceSchema, "tls_cert_expiry_seconds",
+		"Seconds until the server certificate of the TLS channel expires, negative once it has.",
+		[]string{"channel"}, nil,
+	)
+)
+
+// ScrapePerfTLSChannelStatus collects from `performance_schema.tls_channel_status`.
+type ScrapePerfTLSChannelStatus struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfTLSChannelStatus) Name() string {
+	return performanceSchema + ".tls_channel_status"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfTLSChannelStatus) Help() string {
+	return "Collect the server certificate expiry of the TLS channels from performance_schema.tls_channel_status"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfTLSChannelStatus) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfTLSChannelStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	tlsChannelStatusRows, err := db.QueryContext(ctx, perfTLSChannelStatusQuery)
+	if err != nil {
+		return err
+	}
+	defer tlsChannelStatusRows.Close()
+
+	var channel, notAfter string
+	for tlsChannelStatusRows.Next() {
+		if err := tlsChannelStatusRows.Scan(&channel, &notAfter); err != nil {
+			return err
+		}
+		expiry, err := time.Parse(tlsCertTimeLayout, notAfter)
+		if err != nil {
+			// The value is empty when TLS is disabled on the channel.
+			level.Debug(logger).Log("msg", "Unable to parse the certificate expiry", "channel", channel, "value", notAfter, "err", err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaTLSCertExpiryDesc, prometheus.GaugeValue, time.Until(expiry).Seconds(), channel,
+		)
+	}
+	return tlsChannelStatusRows.Err()
+}
+
+// check interface
+var _ Scraper = ScrapePerfTLSChannelStatus{}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapePerfTLSChannelStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// The main certificate expires in 30 days, the admin one expired a day ago.
+	mainExpiry := time.Now().UTC().Add(30 * 24 * time.Hour).Format(tlsCertTimeLayout)
+	adminExpiry := time.Now().UTC().Add(-24 * time.Hour).Format(tlsCertTimeLayout)
+	columns := []string{"CHANNEL", "VALUE"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("mysql_main", mainExpiry).
+		AddRow("mysql_admin", adminExpiry).
+		AddRow("mysql_replication", "")
+	mock.ExpectQuery(sanitizeQuery(perfTLSChannelStatusQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapePerfTLSChannelStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	metricExpected := []struct {
+		channel string
+		value   float64
+	}{
+		{"mysql_main", (30 * 24 * time.Hour).Seconds()},
+		{"mysql_admin", -(24 * time.Hour).Seconds()},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			got := readMetric(<-ch)
+			convey.So(got.labels, convey.ShouldResemble, labelMap{"channel": expect.channel})
+			convey.So(got.metricType, convey.ShouldEqual, dto.MetricType_GAUGE)
+			convey.So(got.value, convey.ShouldAlmostEqual, expect.value, 2)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
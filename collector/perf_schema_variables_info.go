@@ -0,0 +1,82 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `performance_schema.variables_info`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const perfVariablesInfoQuery = `
+	SELECT
+	    VARIABLE_NAME, VARIABLE_SOURCE
+	  FROM performance_schema.variables_info
+	  WHERE VARIABLE_SOURCE != 'COMPILED'
+	`
+
+// Metric descriptors.
+var (
+	performanceSchemaVariableSourceDesc = newNamespacedDesc(
+		performanceSchema, "variable_source",
+		"Source of the variables not set to their compiled-in default, from performance_schema.variables_info.",
+		[]string{"variable_name", "variable_source"}, nil,
+	)
+)
+
+// ScrapePerfVariablesInfo collects from `performance_schema.variables_info`.
+type ScrapePerfVariablesInfo struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapePerfVariablesInfo) Name() string {
+	return performanceSchema + ".variables_info"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapePerfVariablesInfo) Help() string {
+	return "Collect the source of non-default variables from performance_schema.variables_info"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapePerfVariablesInfo) Version() float64 {
+	return 8.0
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapePerfVariablesInfo) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	variablesInfoRows, err := db.QueryContext(ctx, perfVariablesInfoQuery)
+	if err != nil {
+		return err
+	}
+	defer variablesInfoRows.Close()
+
+	var variableName, variableSource string
+	for variablesInfoRows.Next() {
+		if err := variablesInfoRows.Scan(&variableName, &variableSource); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(
+			performanceSchemaVariableSourceDesc, prometheus.GaugeValue, 1,
+			variableName, variableSource,
+		)
+	}
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapePerfVariablesInfo{}
@@ -0,0 +1,70 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Tunable flags.
+var (
+	recentErrorsSize = kingpin.Flag(
+		"exporter.recent-errors",
+		"Number of recent scrape errors kept in memory and served on /debug/errors, 0 disables the endpoint.",
+	).Default("20").Int()
+)
+
+// ScrapeError is a scrape error kept by RecentErrors.
+type ScrapeError struct {
+	Collector string    `json:"collector"`
+	Time      time.Time `json:"time"`
+	Error     string    `json:"error"`
+}
+
+// RecentErrors is a bounded ring buffer of the latest scrape errors.
+type RecentErrors struct {
+	mu     sync.Mutex
+	errors []ScrapeError
+	next   int
+	full   bool
+}
+
+// NewRecentErrors returns a buffer of the last size scrape errors.
+func NewRecentErrors(size int) *RecentErrors {
+	return &RecentErrors{errors: make([]ScrapeError, size)}
+}
+
+// Add records an error, replacing the oldest one when the buffer is full.
+func (r *RecentErrors) Add(collector string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[r.next] = ScrapeError{Collector: collector, Time: time.Now(), Error: err.Error()}
+	r.next = (r.next + 1) % len(r.errors)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// List returns the recorded errors, oldest first.
+func (r *RecentErrors) List() []ScrapeError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]ScrapeError{}, r.errors[:r.next]...)
+	}
+	return append(append([]ScrapeError{}, r.errors[r.next:]...), r.errors[:r.next]...)
+}
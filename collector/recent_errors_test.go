@@ -0,0 +1,46 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestRecentErrors(t *testing.T) {
+	convey.Convey("Recent errors ring buffer", t, func() {
+		recentErrors := NewRecentErrors(3)
+		convey.So(recentErrors.List(), convey.ShouldBeEmpty)
+
+		recentErrors.Add("collect.global_status", errors.New("error 1"))
+		recentErrors.Add("collect.slave_status", errors.New("error 2"))
+		list := recentErrors.List()
+		convey.So(list, convey.ShouldHaveLength, 2)
+		convey.So(list[0].Collector, convey.ShouldEqual, "collect.global_status")
+		convey.So(list[1].Error, convey.ShouldEqual, "error 2")
+
+		// The oldest errors are dropped once full.
+		for i := 3; i <= 5; i++ {
+			recentErrors.Add("collect.global_status", fmt.Errorf("error %d", i))
+		}
+		var texts []string
+		for _, e := range recentErrors.List() {
+			texts = append(texts, e.Error)
+		}
+		convey.So(texts, convey.ShouldResemble, []string{"error 3", "error 4", "error 5"})
+	})
+}
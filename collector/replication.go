@@ -0,0 +1,32 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import "gopkg.in/alecthomas/kingpin.v2"
+
+// Subsystem.
+const replication = "replication"
+
+// Tunable flags.
+var replicationLagSource = kingpin.Flag(
+	"collect.replication.lag-source",
+	"Source of mysql_replication_lag_seconds: slave_status for Seconds_Behind_Master, perf_schema for the transactions being applied by the workers, none to skip it",
+).Default("none").Enum("none", "slave_status", "perf_schema")
+
+// Metric descriptors.
+var replicationLagDesc = newNamespacedDesc(
+	replication, "lag_seconds",
+	"Replication lag in seconds by channel, from the source chosen with --collect.replication.lag-source.",
+	[]string{"source", "channel_name"}, nil,
+)
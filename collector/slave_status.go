@@ -23,6 +23,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const (
@@ -33,6 +34,167 @@ const (
 var slaveStatusQueries = [2]string{"SHOW ALL SLAVES STATUS", "SHOW SLAVE STATUS"}
 var slaveStatusQuerySuffixes = [3]string{" NONBLOCKING", " NOLOCK", ""}
 
+// Maximum length of the error text exposed as a label.
+const slaveStatusLastErrorMaxLength = 256
+
+var slaveStatusLabels = []string{"master_host", "master_uuid", "channel_name", "connection_name"}
+
+// Tunable flags.
+var (
+	slaveStatusIncludeRelayLogSpace = kingpin.Flag(
+		"collect.slave_status.include-relay-log-space",
+		"Collect Relay_Log_Space from SHOW SLAVE STATUS as mysql_slave_status_relay_log_space_bytes",
+	).Default("true").Bool()
+	slaveStatusFilters = kingpin.Flag(
+		"collect.slave_status.filters",
+		"Collect the replication filters from SHOW SLAVE STATUS as mysql_slave_status_replication_filter",
+	).Default("false").Bool()
+	slaveStatusConfig = kingpin.Flag(
+		"collect.slave_status.config",
+		"Collect Auto_Position and the master SSL settings from SHOW SLAVE STATUS as typed gauges",
+	).Default("false").Bool()
+	slaveStatusPositions = kingpin.Flag(
+		"collect.slave_status.positions",
+		"Collect the master and relay log positions from SHOW SLAVE STATUS as typed gauges, with the log file names in mysql_slave_status_log_file_info",
+	).Default("false").Bool()
+	slaveStatusErrantGTID = kingpin.Flag(
+		"collect.slave_status.errant_gtid",
+		"Collect the number of transactions executed with the replica's own server_uuid and not received from a master as mysql_slave_status_errant_transactions",
+	).Default("false").Bool()
+)
+
+const (
+	serverUUIDQuery   = `SELECT @@server_uuid`
+	gtidSubtractQuery = `SELECT GTID_SUBTRACT(?, ?)`
+)
+
+// Metric descriptors.
+var (
+	slaveStatusIORunningDesc = newNamespacedDesc(
+		slaveStatus, "slave_io_running",
+		"Whether the replication I/O thread is running and connected (1 for Yes, 0 for No or Connecting).",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusSQLRunningDesc = newNamespacedDesc(
+		slaveStatus, "slave_sql_running",
+		"Whether the replication SQL thread is running (1 for Yes, 0 for No).",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusLastErrnoDesc = newNamespacedDesc(
+		slaveStatus, "last_errno",
+		"The error number of the last replication error, 0 if none.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusLastErrorInfoDesc = newNamespacedDesc(
+		slaveStatus, "last_error_info",
+		"The (truncated) text of the last replication error, only present when an error occurred.",
+		append([]string{"error"}, slaveStatusLabels...), nil,
+	)
+	slaveStatusRelayLogSpaceDesc = newNamespacedDesc(
+		slaveStatus, "relay_log_space_bytes",
+		"The combined size of all existing relay log files in bytes.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusHeartbeatPeriodDesc = newNamespacedDesc(
+		slaveStatus, "heartbeat_period_seconds",
+		"The replication heartbeat interval in seconds.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusReceivedHeartbeatsDesc = newNamespacedDesc(
+		slaveStatus, "received_heartbeats_total",
+		"Total number of heartbeats received from the master, it stops increasing when replication silently stalls.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusReplicationFilterDesc = newNamespacedDesc(
+		slaveStatus, "replication_filter",
+		"A replication filter set on the channel, by filter type.",
+		append([]string{"type", "value"}, slaveStatusLabels...), nil,
+	)
+	slaveStatusErrantTransactionsDesc = newNamespacedDesc(
+		slaveStatus, "errant_transactions",
+		"Number of executed transactions with the replica's own server_uuid and not received from a master, which break failover. Errant transactions under another server's UUID, e.g. a former master, are not counted as the master's executed set can't be read from the replica.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusAutoPositionDesc = newNamespacedDesc(
+		slaveStatus, "auto_position",
+		"Whether the replica connects to the master with GTID auto-positioning (1 for on, 0 for off).",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusMasterSSLAllowedDesc = newNamespacedDesc(
+		slaveStatus, "master_ssl_allowed",
+		"Whether the connection to the master is encrypted with SSL (1 for Yes, 0 for No or Ignored).",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusMasterSSLVerifyServerCertDesc = newNamespacedDesc(
+		slaveStatus, "master_ssl_verify_server_cert",
+		"Whether the replica verifies the certificate of the master (1 for Yes, 0 for No).",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusReadMasterLogPosDesc = newNamespacedDesc(
+		slaveStatus, "read_master_log_pos",
+		"The position in the current master binary log up to which the I/O thread has read.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusExecMasterLogPosDesc = newNamespacedDesc(
+		slaveStatus, "exec_master_log_pos",
+		"The position in the current master binary log up to which the SQL thread has executed. Subtracted from read_master_log_pos while both are in the same file, it is the apply backlog in bytes.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusRelayLogPosDesc = newNamespacedDesc(
+		slaveStatus, "relay_log_pos",
+		"The position in the current relay log up to which the SQL thread has executed.",
+		slaveStatusLabels, nil,
+	)
+	slaveStatusLogFileInfoDesc = newNamespacedDesc(
+		slaveStatus, "log_file_info",
+		"The binary and relay log files the replication positions refer to.",
+		append([]string{"master_log_file", "relay_master_log_file", "relay_log_file"}, slaveStatusLabels...), nil,
+	)
+	slaveStatusMasterInfoDesc = newNamespacedDesc(
+		slaveStatus, "master_info",
+		"Information about the master the replica is connected to.",
+		[]string{"master_host", "master_port", "channel_name"}, nil,
+	)
+)
+
+// slaveStatusFilterColumns are the SHOW SLAVE STATUS columns with the
+// comma separated lists of replication filters.
+var slaveStatusFilterColumns = []string{
+	"Replicate_Do_DB", "Replicate_Ignore_DB",
+	"Replicate_Do_Table", "Replicate_Ignore_Table",
+	"Replicate_Wild_Do_Table", "Replicate_Wild_Ignore_Table",
+	"Replicate_Rewrite_DB",
+}
+
+// slaveStatusTypedDescs maps SHOW SLAVE STATUS columns to their dedicated metrics.
+var slaveStatusTypedDescs = map[string]struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+}{
+	"Slave_IO_Running":          {slaveStatusIORunningDesc, prometheus.GaugeValue},
+	"Slave_SQL_Running":         {slaveStatusSQLRunningDesc, prometheus.GaugeValue},
+	"Last_Errno":                {slaveStatusLastErrnoDesc, prometheus.GaugeValue},
+	"Relay_Log_Space":           {slaveStatusRelayLogSpaceDesc, prometheus.GaugeValue},
+	"Slave_heartbeat_period":    {slaveStatusHeartbeatPeriodDesc, prometheus.GaugeValue},
+	"Slave_received_heartbeats": {slaveStatusReceivedHeartbeatsDesc, prometheus.CounterValue},
+}
+
+// slaveStatusConfigDescs maps the SHOW SLAVE STATUS configuration columns
+// to their dedicated metrics.
+var slaveStatusConfigDescs = map[string]*prometheus.Desc{
+	"Auto_Position":                 slaveStatusAutoPositionDesc,
+	"Master_SSL_Allowed":            slaveStatusMasterSSLAllowedDesc,
+	"Master_SSL_Verify_Server_Cert": slaveStatusMasterSSLVerifyServerCertDesc,
+}
+
+// slaveStatusPositionDescs maps the SHOW SLAVE STATUS log positions to their
+// dedicated metrics.
+var slaveStatusPositionDescs = map[string]*prometheus.Desc{
+	"Read_Master_Log_Pos": slaveStatusReadMasterLogPosDesc,
+	"Exec_Master_Log_Pos": slaveStatusExecMasterLogPosDesc,
+	"Relay_Log_Pos":       slaveStatusRelayLogPosDesc,
+}
+
 func columnIndex(slaveCols []string, colName string) int {
 	for idx := range slaveCols {
 		if slaveCols[idx] == colName {
@@ -99,6 +261,13 @@ func (ScrapeSlaveStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prome
 		return err
 	}
 
+	// The GTID sets are checked once the rows are closed, as the exporter uses a single connection.
+	type gtidSets struct {
+		labels              []string
+		executed, retrieved string
+	}
+	var replicaGTIDSets []gtidSets
+
 	for slaveStatusRows.Next() {
 		// As the number of columns varies with mysqld versions,
 		// and sql.Scan requires []interface{}, we need to create a
@@ -117,13 +286,106 @@ func (ScrapeSlaveStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prome
 		channelName := columnValue(scanArgs, slaveCols, "Channel_Name")       // MySQL & Percona
 		connectionName := columnValue(scanArgs, slaveCols, "Connection_name") // MariaDB
 
+		if *slaveStatusFilters {
+			for _, col := range slaveStatusFilterColumns {
+				filterType := strings.ToLower(strings.TrimPrefix(col, "Replicate_"))
+				for _, value := range strings.Split(columnValue(scanArgs, slaveCols, col), ",") {
+					if value = strings.TrimSpace(value); value == "" {
+						continue
+					}
+					ch <- prometheus.MustNewConstMetric(
+						slaveStatusReplicationFilterDesc, prometheus.GaugeValue, 1,
+						filterType, value, masterHost, masterUUID, channelName, connectionName,
+					)
+				}
+			}
+		}
+
+		if executed := columnValue(scanArgs, slaveCols, "Executed_Gtid_Set"); *slaveStatusErrantGTID && executed != "" {
+			replicaGTIDSets = append(replicaGTIDSets, gtidSets{
+				labels:    []string{masterHost, masterUUID, channelName, connectionName},
+				executed:  executed,
+				retrieved: columnValue(scanArgs, slaveCols, "Retrieved_Gtid_Set"),
+			})
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			slaveStatusMasterInfoDesc, prometheus.GaugeValue, 1,
+			masterHost, columnValue(scanArgs, slaveCols, "Master_Port"), channelName,
+		)
+
+		if *replicationLagSource == "slave_status" {
+			// Seconds_Behind_Master is NULL while the SQL thread is stopped.
+			if lag, ok := parseStatus(sql.RawBytes(columnValue(scanArgs, slaveCols, "Seconds_Behind_Master"))); ok {
+				channel := channelName
+				if channel == "" {
+					channel = connectionName
+				}
+				ch <- prometheus.MustNewConstMetric(
+					replicationLagDesc, prometheus.GaugeValue, lag, "slave_status", channel,
+				)
+			}
+		}
+
+		if *slaveStatusPositions {
+			ch <- prometheus.MustNewConstMetric(
+				slaveStatusLogFileInfoDesc, prometheus.GaugeValue, 1,
+				columnValue(scanArgs, slaveCols, "Master_Log_File"),
+				columnValue(scanArgs, slaveCols, "Relay_Master_Log_File"),
+				columnValue(scanArgs, slaveCols, "Relay_Log_File"),
+				masterHost, masterUUID, channelName, connectionName,
+			)
+		}
+
+		if lastError := columnValue(scanArgs, slaveCols, "Last_Error"); lastError != "" {
+			lastError = labelValue(lastError, slaveStatusLastErrorMaxLength)
+			ch <- prometheus.MustNewConstMetric(
+				slaveStatusLastErrorInfoDesc, prometheus.GaugeValue, 1,
+				lastError, masterHost, masterUUID, channelName, connectionName,
+			)
+		}
+
 		for i, col := range slaveCols {
-			if value, ok := parseStatus(*scanArgs[i].(*sql.RawBytes)); ok { // Silently skip unparsable values.
+			if col == "Relay_Log_Space" && !*slaveStatusIncludeRelayLogSpace {
+				continue
+			}
+			data := *scanArgs[i].(*sql.RawBytes)
+			if desc, ok := slaveStatusConfigDescs[col]; ok && *slaveStatusConfig {
+				value, ok := parseStatus(data)
+				// Master_SSL_Allowed is Ignored when SSL is configured but not supported, the connection is not encrypted then.
+				if strings.EqualFold(string(data), "Ignored") {
+					value, ok = 0, true
+				}
+				if ok {
+					ch <- prometheus.MustNewConstMetric(
+						desc, prometheus.GaugeValue, value,
+						masterHost, masterUUID, channelName, connectionName,
+					)
+				}
+				continue
+			}
+			if desc, ok := slaveStatusPositionDescs[col]; ok && *slaveStatusPositions {
+				if value, ok := parseStatus(data); ok {
+					ch <- prometheus.MustNewConstMetric(
+						desc, prometheus.GaugeValue, value,
+						masterHost, masterUUID, channelName, connectionName,
+					)
+				}
+				continue
+			}
+			if value, ok := parseStatus(data); ok { // Silently skip unparsable values.
+				if typed, ok := slaveStatusTypedDescs[col]; ok {
+					ch <- prometheus.MustNewConstMetric(
+						typed.desc, typed.valueType, value,
+						masterHost, masterUUID, channelName, connectionName,
+					)
+					continue
+				}
 				ch <- prometheus.MustNewConstMetric(
 					prometheus.NewDesc(
 						prometheus.BuildFQName(namespace, slaveStatus, strings.ToLower(col)),
 						"Generic metric from SHOW SLAVE STATUS.",
-						[]string{"master_host", "master_uuid", "channel_name", "connection_name"},
+						slaveStatusLabels,
 						nil,
 					),
 					prometheus.UntypedValue,
@@ -133,8 +395,46 @@ func (ScrapeSlaveStatus) Scrape(ctx context.Context, db *sql.DB, ch chan<- prome
 			}
 		}
 	}
+	if err := slaveStatusRows.Err(); err != nil {
+		return err
+	}
+	if len(replicaGTIDSets) == 0 {
+		return nil
+	}
+	slaveStatusRows.Close()
+
+	var serverUUID string
+	if err := db.QueryRowContext(ctx, serverUUIDQuery).Scan(&serverUUID); err != nil {
+		return err
+	}
+	// Only the replica's own GTIDs are checked, the master's executed set
+	// would be needed to tell errant GTIDs of other sources apart.
+	for _, sets := range replicaGTIDSets {
+		errant := gtidSetSource(sets.executed, serverUUID)
+		if errant != "" && sets.retrieved != "" {
+			if err := db.QueryRowContext(ctx, gtidSubtractQuery, errant, sets.retrieved).Scan(&errant); err != nil {
+				return err
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(
+			slaveStatusErrantTransactionsDesc, prometheus.GaugeValue, float64(gtidSetCount(errant)),
+			sets.labels...,
+		)
+	}
 	return nil
 }
 
+// gtidSetSource returns the part of a GTID set originating from the server with the given UUID.
+func gtidSetSource(set, uuid string) string {
+	var gtids []string
+	for _, sourceGTIDs := range strings.Split(set, ",") {
+		sourceGTIDs = strings.TrimSpace(sourceGTIDs)
+		if source := strings.SplitN(sourceGTIDs, ":", 2)[0]; strings.EqualFold(source, uuid) {
+			gtids = append(gtids, sourceGTIDs)
+		}
+	}
+	return strings.Join(gtids, ",")
+}
+
 // check interface
 var _ Scraper = ScrapeSlaveStatus{}
@@ -15,6 +15,7 @@ package collector
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -22,6 +23,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 func TestScrapeSlaveStatus(t *testing.T) {
@@ -45,9 +47,10 @@ func TestScrapeSlaveStatus(t *testing.T) {
 	}()
 
 	counterExpected := []MetricResult{
+		{labels: labelMap{"channel_name": "", "master_host": "127.0.0.1", "master_port": ""}, value: 1, metricType: dto.MetricType_GAUGE},
 		{labels: labelMap{"channel_name": "", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}, value: 1, metricType: dto.MetricType_UNTYPED},
-		{labels: labelMap{"channel_name": "", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}, value: 0, metricType: dto.MetricType_UNTYPED},
-		{labels: labelMap{"channel_name": "", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}, value: 1, metricType: dto.MetricType_UNTYPED},
+		{labels: labelMap{"channel_name": "", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"channel_name": "", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}, value: 1, metricType: dto.MetricType_GAUGE},
 		{labels: labelMap{"channel_name": "", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}, value: 2, metricType: dto.MetricType_UNTYPED},
 	}
 	convey.Convey("Metrics comparison", t, func() {
@@ -62,3 +65,457 @@ func TestScrapeSlaveStatus(t *testing.T) {
 		t.Errorf("there were unfulfilled exceptions: %s", err)
 	}
 }
+
+func TestScrapeSlaveStatusLastError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Master_Host", "Master_Port", "Slave_IO_Running", "Slave_SQL_Running", "Last_Errno", "Last_Error", "Channel_Name"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("127.0.0.1", "3306", "Yes", "No", "1062", "Duplicate entry '1' for key 'PRIMARY'", "ch1")
+	mock.ExpectQuery(sanitizeQuery("SHOW SLAVE STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	labels := labelMap{"channel_name": "ch1", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}
+	counterExpected := []MetricResult{
+		{labels: labelMap{"channel_name": "ch1", "master_host": "127.0.0.1", "master_port": "3306"}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"error": "Duplicate entry '1' for key 'PRIMARY'", "channel_name": "ch1", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labels, value: 3306, metricType: dto.MetricType_UNTYPED},
+		{labels: labels, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labels, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labels, value: 1062, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range counterExpected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeSlaveStatusLastErrorMultiByte(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// The 2-byte "é" straddles the length limit, cutting the bytes there would leave invalid UTF-8.
+	prefix := strings.Repeat("a", slaveStatusLastErrorMaxLength-1)
+	columns := []string{"Master_Host", "Last_Error", "Channel_Name"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("127.0.0.1", prefix+"é table", "ch1")
+	mock.ExpectQuery(sanitizeQuery("SHOW SLAVE STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	convey.Convey("Metrics comparison", t, func() {
+		// Skip master_info.
+		<-ch
+		got := readMetric(<-ch)
+		convey.So(got.labels["error"], convey.ShouldEqual, prefix)
+		for range ch {
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeSlaveStatusRelayLogSpace(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.slave_status.include-relay-log-space"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Master_Host", "Relay_Log_Space", "Channel_Name"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("127.0.0.1", "1073741824", "ch1").
+		AddRow("127.0.0.2", "2048", "ch2")
+	mock.ExpectQuery(sanitizeQuery("SHOW SLAVE STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range []struct {
+			channel, host string
+			value         float64
+		}{
+			{"ch1", "127.0.0.1", 1073741824},
+			{"ch2", "127.0.0.2", 2048},
+		} {
+			// Skip master_info.
+			<-ch
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"mysql_slave_status_relay_log_space_bytes"`)
+			convey.So(readMetric(m), convey.ShouldResemble, MetricResult{
+				labels:     labelMap{"channel_name": expect.channel, "connection_name": "", "master_host": expect.host, "master_uuid": ""},
+				value:      expect.value,
+				metricType: dto.MetricType_GAUGE,
+			})
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeSlaveStatusHeartbeats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// SHOW ALL SLAVES STATUS output of MariaDB.
+	columns := []string{"Connection_name", "Master_Host", "Slave_IO_Running", "Slave_heartbeat_period", "Slave_received_heartbeats"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("main", "127.0.0.1", "Yes", "30.000", "1442")
+	mock.ExpectQuery(sanitizeQuery("SHOW ALL SLAVES STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	labels := labelMap{"channel_name": "", "connection_name": "main", "master_host": "127.0.0.1", "master_uuid": ""}
+	metricExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_slave_status_master_info", MetricResult{labels: labelMap{"channel_name": "", "master_host": "127.0.0.1", "master_port": ""}, value: 1, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_slave_io_running", MetricResult{labels: labels, value: 1, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_heartbeat_period_seconds", MetricResult{labels: labels, value: 30, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_received_heartbeats_total", MetricResult{labels: labels, value: 1442, metricType: dto.MetricType_COUNTER}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeSlaveStatusErrantGTID(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.slave_status.errant_gtid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	const (
+		masterUUID  = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+		replicaUUID = "4f22ab58-71ca-11e1-9e33-c80aa9429563"
+	)
+	columns := []string{"Master_Host", "Master_UUID", "Retrieved_Gtid_Set", "Executed_Gtid_Set", "Channel_Name"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("127.0.0.1", masterUUID, masterUUID+":1-100", masterUUID+":1-100,\n"+replicaUUID+":1-3:7", "")
+	mock.ExpectQuery(sanitizeQuery("SHOW SLAVE STATUS")).WillReturnRows(rows)
+	mock.ExpectQuery(sanitizeQuery(serverUUIDQuery)).WillReturnRows(sqlmock.NewRows([]string{"@@server_uuid"}).AddRow(replicaUUID))
+	mock.ExpectQuery(sanitizeQuery(gtidSubtractQuery)).
+		WithArgs(replicaUUID+":1-3:7", masterUUID+":1-100").
+		WillReturnRows(sqlmock.NewRows([]string{"GTID_SUBTRACT"}).AddRow(replicaUUID + ":1-3:7"))
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	convey.Convey("Metrics comparison", t, func() {
+		// Skip master_info.
+		<-ch
+		m := <-ch
+		convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"mysql_slave_status_errant_transactions"`)
+		convey.So(readMetric(m), convey.ShouldResemble, MetricResult{
+			labels:     labelMap{"channel_name": "", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": masterUUID},
+			value:      4,
+			metricType: dto.MetricType_GAUGE,
+		})
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestGTIDSetSource(t *testing.T) {
+	convey.Convey("GTIDs of a source", t, func() {
+		set := "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5,\n4f22ab58-71ca-11e1-9e33-c80aa9429563:1-3:7"
+		convey.So(gtidSetSource(set, "4f22ab58-71ca-11e1-9e33-c80aa9429563"), convey.ShouldEqual, "4f22ab58-71ca-11e1-9e33-c80aa9429563:1-3:7")
+		convey.So(gtidSetSource(set, "3e11fa47-71ca-11e1-9e33-c80aa9429562"), convey.ShouldEqual, "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5")
+		convey.So(gtidSetSource(set, "5a33bc69-71ca-11e1-9e33-c80aa9429564"), convey.ShouldEqual, "")
+	})
+}
+
+func TestScrapeSlaveStatusFilters(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.slave_status.filters"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Master_Host", "Replicate_Do_DB", "Replicate_Ignore_DB", "Replicate_Wild_Ignore_Table", "Channel_Name"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("127.0.0.1", "", "scratch,tmp", "audit.%", "ch1")
+	mock.ExpectQuery(sanitizeQuery("SHOW SLAVE STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	labels := func(filterType, value string) labelMap {
+		return labelMap{"type": filterType, "value": value, "channel_name": "ch1", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}
+	}
+	metricExpected := []MetricResult{
+		{labels: labels("ignore_db", "scratch"), value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labels("ignore_db", "tmp"), value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labels("wild_ignore_table", "audit.%"), value: 1, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"mysql_slave_status_replication_filter"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect)
+		}
+		// Only master_info follows.
+		<-ch
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeSlaveStatusConfig(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.slave_status.config"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Master_Host", "Master_SSL_Allowed", "Master_SSL_Verify_Server_Cert", "Auto_Position", "Channel_Name"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("127.0.0.1", "Ignored", "No", "1", "ch1")
+	mock.ExpectQuery(sanitizeQuery("SHOW SLAVE STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	labels := labelMap{"channel_name": "ch1", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}
+	metricExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_slave_status_master_ssl_allowed", MetricResult{labels: labels, value: 0, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_master_ssl_verify_server_cert", MetricResult{labels: labels, value: 0, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_auto_position", MetricResult{labels: labels, value: 1, metricType: dto.MetricType_GAUGE}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		// Skip master_info.
+		<-ch
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeSlaveStatusReplicationLag(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.replication.lag-source=slave_status"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	// MariaDB multi-source replication names connections instead of channels.
+	columns := []string{"Connection_name", "Master_Host", "Seconds_Behind_Master"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("east", "10.0.0.1", "7").
+		AddRow("west", "10.0.0.2", nil)
+	mock.ExpectQuery(sanitizeQuery("SHOW ALL SLAVES STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	convey.Convey("Metrics comparison", t, func() {
+		var lags []MetricResult
+		for m := range ch {
+			if strings.Contains(m.Desc().String(), `"mysql_replication_lag_seconds"`) {
+				lags = append(lags, readMetric(m))
+			}
+		}
+		convey.So(lags, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{"source": "slave_status", "channel_name": "east"}, value: 7, metricType: dto.MetricType_GAUGE},
+		})
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeSlaveStatusPositions(t *testing.T) {
+	_, err := kingpin.CommandLine.Parse([]string{"--collect.slave_status.positions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	columns := []string{"Master_Host", "Master_Log_File", "Read_Master_Log_Pos", "Relay_Log_File", "Relay_Log_Pos", "Relay_Master_Log_File", "Exec_Master_Log_Pos", "Channel_Name"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("127.0.0.1", "mysql-bin.000012", "2000", "relay-bin.000004", "800", "mysql-bin.000012", "1500", "ch1")
+	mock.ExpectQuery(sanitizeQuery("SHOW SLAVE STATUS")).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeSlaveStatus{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	labels := labelMap{"channel_name": "ch1", "connection_name": "", "master_host": "127.0.0.1", "master_uuid": ""}
+	fileLabels := labelMap{"master_log_file": "mysql-bin.000012", "relay_master_log_file": "mysql-bin.000012", "relay_log_file": "relay-bin.000004"}
+	for k, v := range labels {
+		fileLabels[k] = v
+	}
+	metricExpected := []struct {
+		name   string
+		result MetricResult
+	}{
+		{"mysql_slave_status_log_file_info", MetricResult{labels: fileLabels, value: 1, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_read_master_log_pos", MetricResult{labels: labels, value: 2000, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_relay_log_pos", MetricResult{labels: labels, value: 800, metricType: dto.MetricType_GAUGE}},
+		{"mysql_slave_status_exec_master_log_pos", MetricResult{labels: labels, value: 1500, metricType: dto.MetricType_GAUGE}},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		// Skip master_info.
+		<-ch
+		for _, expect := range metricExpected {
+			m := <-ch
+			convey.So(m.Desc().String(), convey.ShouldContainSubstring, `"`+expect.name+`"`)
+			convey.So(readMetric(m), convey.ShouldResemble, expect.result)
+		}
+		_, ok := <-ch
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape the TLS status of the exporter's connection from `SHOW SESSION STATUS`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Subsystem.
+	ssl = "ssl"
+	// Query.
+	sslConnectionQuery = `SHOW SESSION STATUS WHERE Variable_name IN ('Ssl_cipher', 'Ssl_version')`
+)
+
+// Metric descriptors.
+var (
+	sslConnectionInfoDesc = newNamespacedDesc(
+		ssl, "connection_info",
+		"The TLS cipher and version of the exporter's connection, 1 when it uses TLS and 0 with empty labels otherwise.",
+		[]string{"cipher", "version"}, nil,
+	)
+)
+
+// ScrapeSSLConnectionInfo collects from `SHOW SESSION STATUS`.
+type ScrapeSSLConnectionInfo struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeSSLConnectionInfo) Name() string {
+	return "ssl_connection_info"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeSSLConnectionInfo) Help() string {
+	return "Collect the TLS cipher and version of the exporter's own connection from SHOW SESSION STATUS"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeSSLConnectionInfo) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeSSLConnectionInfo) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	sslRows, err := db.QueryContext(ctx, sslConnectionQuery)
+	if err != nil {
+		return err
+	}
+	defer sslRows.Close()
+
+	var name, value string
+	status := map[string]string{}
+	for sslRows.Next() {
+		if err := sslRows.Scan(&name, &value); err != nil {
+			return err
+		}
+		status[name] = value
+	}
+	if err := sslRows.Err(); err != nil {
+		return err
+	}
+
+	// Ssl_cipher is empty on a connection without TLS.
+	cipher := status["Ssl_cipher"]
+	if cipher == "" {
+		ch <- prometheus.MustNewConstMetric(sslConnectionInfoDesc, prometheus.GaugeValue, 0, "", "")
+		return nil
+	}
+	ch <- prometheus.MustNewConstMetric(sslConnectionInfoDesc, prometheus.GaugeValue, 1, cipher, status["Ssl_version"])
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeSSLConnectionInfo{}
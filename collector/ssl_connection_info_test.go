@@ -0,0 +1,76 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapeSSLConnectionInfo(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cipher   string
+		version  string
+		expected MetricResult
+	}{
+		{
+			name:     "TLS",
+			cipher:   "TLS_AES_256_GCM_SHA384",
+			version:  "TLSv1.3",
+			expected: MetricResult{labels: labelMap{"cipher": "TLS_AES_256_GCM_SHA384", "version": "TLSv1.3"}, value: 1, metricType: dto.MetricType_GAUGE},
+		},
+		{
+			name:     "plain",
+			expected: MetricResult{labels: labelMap{"cipher": "", "version": ""}, value: 0, metricType: dto.MetricType_GAUGE},
+		},
+	} {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("error opening a stub database connection: %s", err)
+		}
+
+		columns := []string{"Variable_name", "Value"}
+		rows := sqlmock.NewRows(columns).
+			AddRow("Ssl_cipher", tc.cipher).
+			AddRow("Ssl_version", tc.version)
+		mock.ExpectQuery(sanitizeQuery(sslConnectionQuery)).WillReturnRows(rows)
+
+		ch := make(chan prometheus.Metric)
+		go func() {
+			if err := (ScrapeSSLConnectionInfo{}).Scrape(context.Background(), db, ch, log.NewNopLogger()); err != nil {
+				t.Errorf("error calling function on test: %s", err)
+			}
+			close(ch)
+		}()
+
+		convey.Convey("Metrics comparison "+tc.name, t, func() {
+			convey.So(readMetric(<-ch), convey.ShouldResemble, tc.expected)
+			_, ok := <-ch
+			convey.So(ok, convey.ShouldBeFalse)
+		})
+
+		// Ensure all SQL queries were executed
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled exceptions: %s", err)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `XA RECOVER`.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Subsystem.
+	xa = "xa"
+	// Query.
+	xaRecoverQuery = `XA RECOVER`
+	// ER_SPECIFIC_ACCESS_DENIED_ERROR, returned without XA_RECOVER_ADMIN on MySQL 8.0.
+	specificAccessDeniedError = 1227
+)
+
+// Metric descriptors.
+var (
+	xaPreparedTransactionsDesc = newNamespacedDesc(
+		xa, "prepared_transactions",
+		"The number of XA transactions in the prepared state. They survive disconnects and hold their locks until committed or rolled back.",
+		nil, nil,
+	)
+)
+
+// ScrapeXARecover collects from `XA RECOVER`.
+type ScrapeXARecover struct{}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeXARecover) Name() string {
+	return "xa_recover"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeXARecover) Help() string {
+	return "Collect the number of prepared XA transactions from XA RECOVER"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeXARecover) Version() float64 {
+	return 5.1
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (ScrapeXARecover) Scrape(ctx context.Context, db *sql.DB, ch chan<- prometheus.Metric, logger log.Logger) error {
+	xaRows, err := db.QueryContext(ctx, xaRecoverQuery)
+	if err != nil {
+		var mysqlErr *gomysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == specificAccessDeniedError {
+			level.Debug(logger).Log("msg", "Skipping the prepared XA transactions, XA RECOVER is denied", "err", err)
+			return nil
+		}
+		return err
+	}
+	defer xaRows.Close()
+
+	var prepared float64
+	for xaRows.Next() {
+		prepared++
+	}
+	if err := xaRows.Err(); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(
+		xaPreparedTransactionsDesc, prometheus.GaugeValue, prepared,
+	)
+	return nil
+}
+
+// check interface
+var _ Scraper = ScrapeXARecover{}
@@ -17,13 +17,20 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/chatmoo/mysqld_exporter/collector"
@@ -33,6 +40,7 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/promlog"
 	"github.com/prometheus/common/promlog/flag"
 	"github.com/prometheus/common/version"
@@ -60,16 +68,55 @@ var (
 		"config.my-cnf",
 		"Path to .my.cnf file to read MySQL credentials from.",
 	).Default(path.Join(os.Getenv("HOME"), ".my.cnf")).String()
+	skipMycnf = kingpin.Flag(
+		"config.skip-my-cnf",
+		"Never read the .my.cnf file, the DSN must come from DATA_SOURCE_NAME.",
+	).Default("false").Bool()
 	tlsInsecureSkipVerify = kingpin.Flag(
 		"tls.insecure-skip-verify",
 		"Ignore certificate and server verification when using a tls connection.",
 	).Bool()
+	collectorsEnabled = kingpin.Flag(
+		"collectors.enabled",
+		"Comma separated list of collectors to enable, e.g. global_status,slave_status. When set, all other collectors are disabled, regardless of their --collect.* flag.",
+	).Default("").String()
+	maxConcurrentScrapes = kingpin.Flag(
+		"scrape.max-concurrent",
+		"Maximum number of scrapes collecting from MySQL at once, 0 for no limit. Excess scrapes wait for their timeout, then fail with 503.",
+	).Default("0").Int()
+	scrapeWarmup = kingpin.Flag(
+		"scrape.warmup",
+		"Run one collection at startup, before serving, so that the first scrape doesn't hit cold server caches.",
+	).Default("false").Bool()
+	metricNamespace = kingpin.Flag(
+		"metric.namespace",
+		"Prefix of the MySQL metric names, to tell apart series of several exporters.",
+	).Default(collector.Namespace).Action(validatePrometheusName("metric.namespace")).String()
+	shardLabelQuery = kingpin.Flag(
+		"collect.shard-label-query",
+		"Query whose first value is added to all MySQL metrics as the --collect.shard-label-name label. It runs until it succeeds once.",
+	).Default("").String()
+	shardLabelName = kingpin.Flag(
+		"collect.shard-label-name",
+		"Name of the label set from --collect.shard-label-query.",
+	).Default("shard").Action(validatePrometheusName("collect.shard-label-name")).String()
 	dsn string
+	// dsnMu guards dsn, which SIGHUP reloads.
+	dsnMu sync.RWMutex
 )
 
+// warmupTimeout bounds the startup collection of --scrape.warmup.
+const warmupTimeout = time.Minute
+
+// reloadTimeout bounds the connection check of a DSN reloaded on SIGHUP.
+const reloadTimeout = 30 * time.Second
+
+var prometheusNameRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // scrapers lists all possible collection methods and if they should be enabled by default.
 var scrapers = map[collector.Scraper]bool{
 	collector.ScrapeGlobalStatus{}:                        true,
+	collector.ScrapeGlobalVariables{}:                     true,
 	collector.ScrapeSlaveStatus{}:                         true,
 	collector.ScrapeProcesslist{}:                         true,
 	collector.ScrapeUser{}:                                false,
@@ -86,6 +133,97 @@ var scrapers = map[collector.Scraper]bool{
 	collector.ScrapePerfReplicationGroupMemberStats{}:     true,
 	collector.ScrapePerfReplicationApplierStatsByWorker{}: true,
 	collector.ScrapeEngineInnodbStatus{}:                  false,
+	collector.ScrapeInfoSchemaFiles{}:                     false,
+	collector.ScrapePerfDataLocks{}:                       false,
+	collector.ScrapeInfoSchemaForeignKeys{}:               false,
+	collector.ScrapeInnodbPurgeHistory{}:                  false,
+	collector.ScrapePerfEventsTransactions{}:              false,
+	collector.ScrapePerfStatusByThread{}:                  false,
+	collector.ScrapeInfoSchemaInnodbTables{}:              false,
+	collector.ScrapePerfVariablesInfo{}:                   false,
+	collector.ScrapeInfoSchemaSchemaComplexity{}:          false,
+	collector.ScrapeInfoSchemaPartitions{}:                false,
+	collector.ScrapePerfReplicationConnectionStatus{}:     false,
+	&collector.ScrapePerfErrorLog{}:                       false,
+	collector.ScrapeInfoSchemaIndexHygiene{}:              false,
+	collector.ScrapeInfoSchemaInnodbDataSize{}:            false,
+	collector.ScrapeClockSkew{}:                           true,
+	collector.ScrapePerfSocketSummary{}:                   false,
+	collector.ScrapeInnodbBufferPoolResize{}:              false,
+	collector.ScrapeSysInnodbBufferStatsByTable{}:         false,
+	collector.ScrapePerfTLSChannelStatus{}:                false,
+	collector.ScrapeInfoSchemaInnodbTemp{}:                false,
+	collector.ScrapeAccountLimits{}:                       false,
+	collector.ScrapeInfoSchemaTableStatsStaleness{}:       false,
+	collector.ScrapeInfoSchemaInnodbUndo{}:                false,
+	collector.ScrapeBinlogDumpThreads{}:                   false,
+	collector.ScrapePerfKeyring{}:                         false,
+	collector.ScrapeInfoSchemaEncryption{}:                false,
+	collector.ScrapeXARecover{}:                           false,
+	collector.ScrapeSSLConnectionInfo{}:                   false,
+	collector.ScrapePerfMemoryByThread{}:                  false,
+	collector.ScrapePerfConnectAttrs{}:                    false,
+}
+
+// parseCollectorsEnabled returns the scrapers named in a comma separated list.
+func parseCollectorsEnabled(list string) ([]collector.Scraper, error) {
+	byName := make(map[string]collector.Scraper, len(scrapers))
+	for scraper := range scrapers {
+		byName[scraper.Name()] = scraper
+	}
+
+	var enabled []collector.Scraper
+	seen := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		scraper, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown collector %q", name)
+		}
+		seen[name] = true
+		enabled = append(enabled, scraper)
+	}
+	return enabled, nil
+}
+
+// resolveDSN returns the DSN from the DATA_SOURCE_NAME environment variable if set,
+// otherwise from the my.cnf file unless reading it is disabled.
+func resolveDSN(envDSN string, skipMycnf bool, mycnf string) (string, error) {
+	if envDSN != "" {
+		return envDSN, nil
+	}
+	if skipMycnf {
+		return "", errors.New("DATA_SOURCE_NAME must be set when --config.skip-my-cnf is used")
+	}
+	return parseMycnf(mycnf)
+}
+
+// currentDSN returns the DSN the scrapes connect with.
+func currentDSN() string {
+	dsnMu.RLock()
+	defer dsnMu.RUnlock()
+	return dsn
+}
+
+// reloadDSN resolves the DSN again, e.g. after the password in the my.cnf
+// file was rotated. The scrapes keep using the previous one unless a
+// connection with the new one succeeds. The enabled scrapers are set by
+// flags and aren't reloaded.
+func reloadDSN(ctx context.Context) error {
+	newDSN, err := resolveDSN(os.Getenv("DATA_SOURCE_NAME"), *skipMycnf, *configMycnf)
+	if err != nil {
+		return err
+	}
+	if err := collector.CheckDSN(ctx, newDSN); err != nil {
+		return err
+	}
+	dsnMu.Lock()
+	defer dsnMu.Unlock()
+	dsn = newDSN
+	return nil
 }
 
 func parseMycnf(config interface{}) (string, error) {
@@ -160,11 +298,147 @@ func customizeTLS(sslCA string, sslCert string, sslKey string) error {
 	return nil
 }
 
+// validatePrometheusName returns an action checking that the flag is usable
+// as a metric namespace or a label name.
+func validatePrometheusName(name string) kingpin.Action {
+	return func(ctx *kingpin.ParseContext) error {
+		for _, element := range ctx.Elements {
+			flag, ok := element.Clause.(*kingpin.FlagClause)
+			if !ok || flag.Model().Name != name || element.Value == nil {
+				continue
+			}
+			if !prometheusNameRE.MatchString(*element.Value) {
+				return fmt.Errorf("invalid --%s %q, must match %s", name, *element.Value, prometheusNameRE)
+			}
+		}
+		return nil
+	}
+}
+
+// shardLabeler holds the label set from --collect.shard-label-query. The
+// query runs on each scrape until it succeeds, its result is then reused.
+type shardLabeler struct {
+	mu       sync.Mutex
+	resolved bool
+	labels   prometheus.Labels
+}
+
+func (s *shardLabeler) get(ctx context.Context, logger log.Logger) prometheus.Labels {
+	if *shardLabelQuery == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resolved {
+		return s.labels
+	}
+
+	value, err := collector.ShardLabelValue(ctx, currentDSN(), *shardLabelQuery)
+	if err != nil {
+		level.Error(logger).Log("msg", "Error querying the shard label", "err", err)
+		return nil
+	}
+	s.resolved = true
+	if value == "" {
+		level.Warn(logger).Log("msg", "Shard label query returned no value, metrics won't have the label", "label", *shardLabelName)
+		return nil
+	}
+	s.labels = prometheus.Labels{*shardLabelName: value}
+	return s.labels
+}
+
+// labelGatherer adds the labels to the gathered metrics. A metric which
+// already has a label of the same name keeps its own value, as adding it
+// again would fail the whole scrape.
+type labelGatherer struct {
+	prometheus.Gatherer
+	labels prometheus.Labels
+	logger log.Logger
+}
+
+func (g labelGatherer) Gather() ([]*dto.MetricFamily, error) {
+	mfs, err := g.Gatherer.Gather()
+	for _, mf := range mfs {
+		for name, value := range g.labels {
+			name, value := name, value
+			collides := false
+			for _, m := range mf.Metric {
+				if hasLabel(m, name) {
+					collides = true
+					continue
+				}
+				m.Label = append(m.Label, &dto.LabelPair{Name: &name, Value: &value})
+				sort.Slice(m.Label, func(i, j int) bool { return m.Label[i].GetName() < m.Label[j].GetName() })
+			}
+			if collides {
+				level.Warn(g.logger).Log("msg", "Metric already has the label, it keeps its own value", "metric", mf.GetName(), "label", name)
+			}
+		}
+	}
+	return mfs, err
+}
+
+func hasLabel(m *dto.Metric, name string) bool {
+	for _, pair := range m.Label {
+		if pair.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	prometheus.MustRegister(version.NewCollector("mysqld_exporter"))
 }
 
+// scrapeLimiter is a semaphore bounding the number of concurrent scrapes.
+// A nil scrapeLimiter doesn't limit anything.
+type scrapeLimiter chan struct{}
+
+func newScrapeLimiter(max int) scrapeLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return make(scrapeLimiter, max)
+}
+
+// acquire waits for a free slot until ctx is done, and reports whether it got one.
+func (l scrapeLimiter) acquire(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (l scrapeLimiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// warmup runs one collection of the scrapers and logs its duration.
+func warmup(metrics collector.Metrics, scrapers []collector.Scraper, logger log.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), warmupTimeout)
+	defer cancel()
+
+	start := time.Now()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector.New(ctx, currentDSN(), metrics.WithoutOnlyChanged(), scrapers, logger))
+	if _, err := registry.Gather(); err != nil {
+		level.Warn(logger).Log("msg", "Error during warmup collection", "err", err)
+	}
+	level.Info(logger).Log("msg", "Warmup collection done", "duration_seconds", time.Since(start).Seconds())
+}
+
 func newHandler(metrics collector.Metrics, scrapers []collector.Scraper, logger log.Logger) http.HandlerFunc {
+	// Shared by all requests to bound the number of concurrent scrapes.
+	limiter := newScrapeLimiter(*maxConcurrentScrapes)
+	shardLabels := &shardLabeler{}
 	return func(w http.ResponseWriter, r *http.Request) {
 		filteredScrapers := scrapers
 		params := r.URL.Query()["collect[]"]
@@ -193,6 +467,16 @@ func newHandler(metrics collector.Metrics, scrapers []collector.Scraper, logger
 		}
 		level.Debug(logger).Log("msg", "collect[] params", "params", strings.Join(params, ","))
 
+		if !limiter.acquire(ctx) {
+			level.Warn(logger).Log("msg", "Scrape rejected, too many concurrent scrapes", "max_concurrent", *maxConcurrentScrapes)
+			metrics.ScrapesRejected.Inc()
+			http.Error(w, "Too many concurrent scrapes", http.StatusServiceUnavailable)
+			return
+		}
+		defer limiter.release()
+		metrics.ScrapesInFlight.Inc()
+		defer metrics.ScrapesInFlight.Dec()
+
 		// Check if we have some "collect[]" query parameters.
 		if len(params) > 0 {
 			filters := make(map[string]bool)
@@ -209,11 +493,19 @@ func newHandler(metrics collector.Metrics, scrapers []collector.Scraper, logger
 		}
 
 		registry := prometheus.NewRegistry()
-		registry.MustRegister(collector.New(ctx, dsn, metrics, filteredScrapers, logger))
+		if err := registry.Register(collector.New(ctx, currentDSN(), metrics, filteredScrapers, logger)); err != nil {
+			level.Error(logger).Log("msg", "Error registering the collector", "err", err)
+			http.Error(w, "Error registering the collector", http.StatusInternalServerError)
+			return
+		}
 
+		var gatherer prometheus.Gatherer = registry
+		if labels := shardLabels.get(ctx, logger); labels != nil {
+			gatherer = labelGatherer{Gatherer: gatherer, labels: labels, logger: logger}
+		}
 		gatherers := prometheus.Gatherers{
 			prometheus.DefaultGatherer,
-			registry,
+			gatherer,
 		}
 		// Delegate http serving to Prometheus client library, which will call collector.Collect.
 		h := promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{})
@@ -221,6 +513,16 @@ func newHandler(metrics collector.Metrics, scrapers []collector.Scraper, logger
 	}
 }
 
+// newRecentErrorsHandler serves the recent scrape errors as JSON, oldest first.
+func newRecentErrorsHandler(recentErrors *collector.RecentErrors, logger log.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(recentErrors.List()); err != nil {
+			level.Error(logger).Log("msg", "Error writing the recent scrape errors", "err", err)
+		}
+	}
+}
+
 func main() {
 	// Generate ON/OFF flags for all scrapers.
 	scraperFlags := map[collector.Scraper]*bool{}
@@ -245,6 +547,7 @@ func main() {
 	kingpin.HelpFlag.Short('h')
 	kingpin.Parse()
 	logger := promlog.New(promlogConfig)
+	collector.SetNamespace(*metricNamespace)
 
 	// landingPage contains the HTML served at '/'.
 	// TODO: Make this nicer and more informative.
@@ -260,25 +563,61 @@ func main() {
 	level.Info(logger).Log("msg", "Starting msqyld_exporter", "version", version.Info())
 	level.Info(logger).Log("msg", "Build context", version.BuildContext())
 
-	dsn = os.Getenv("DATA_SOURCE_NAME")
-	if len(dsn) == 0 {
-		var err error
-		if dsn, err = parseMycnf(*configMycnf); err != nil {
-			level.Info(logger).Log("msg", "Error parsing my.cnf", "file", *configMycnf, "err", err)
-			os.Exit(1)
-		}
+	var err error
+	if dsn, err = resolveDSN(os.Getenv("DATA_SOURCE_NAME"), *skipMycnf, *configMycnf); err != nil {
+		level.Info(logger).Log("msg", "Error getting the data source name", "file", *configMycnf, "err", err)
+		os.Exit(1)
 	}
 
 	// Register only scrapers enabled by flag.
 	enabledScrapers := []collector.Scraper{}
-	for scraper, enabled := range scraperFlags {
-		if *enabled {
+	if *collectorsEnabled != "" {
+		var err error
+		if enabledScrapers, err = parseCollectorsEnabled(*collectorsEnabled); err != nil {
+			level.Error(logger).Log("msg", "Error parsing --collectors.enabled", "err", err)
+			os.Exit(1)
+		}
+		for _, scraper := range enabledScrapers {
 			level.Info(logger).Log("msg", "Scraper enabled", "scraper", scraper.Name())
-			enabledScrapers = append(enabledScrapers, scraper)
 		}
+	} else {
+		for scraper, enabled := range scraperFlags {
+			if *enabled {
+				level.Info(logger).Log("msg", "Scraper enabled", "scraper", scraper.Name())
+				enabledScrapers = append(enabledScrapers, scraper)
+			}
+		}
+	}
+	if err := collector.CheckRequiredCollectors(enabledScrapers); err != nil {
+		level.Error(logger).Log("msg", "Error checking --collect.required", "err", err)
+		os.Exit(1)
+	}
+	collector.LogSkippedScrapers(dsn, enabledScrapers, logger)
+
+	metrics := collector.NewMetrics()
+	if *scrapeWarmup {
+		warmup(metrics, enabledScrapers, logger)
 	}
-	handlerFunc := newHandler(collector.NewMetrics(), enabledScrapers, logger)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+			if err := reloadDSN(ctx); err != nil {
+				level.Error(logger).Log("msg", "Error reloading the data source name, keeping the previous one", "file", *configMycnf, "err", err)
+			} else {
+				level.Info(logger).Log("msg", "Reloaded the data source name", "file", *configMycnf)
+			}
+			cancel()
+		}
+	}()
+
+	handlerFunc := newHandler(metrics, enabledScrapers, logger)
 	http.Handle(*metricPath, promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, handlerFunc))
+	if metrics.RecentErrors != nil {
+		http.Handle("/debug/errors", newRecentErrorsHandler(metrics.RecentErrors, logger))
+	}
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write(landingPage)
 	})
@@ -15,21 +15,30 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"os/exec"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/chatmoo/mysqld_exporter/collector"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/smartystreets/goconvey/convey"
+	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 func TestParseMycnf(t *testing.T) {
@@ -146,12 +155,193 @@ func TestParseMycnf(t *testing.T) {
 	})
 }
 
+func TestParseCollectorsEnabled(t *testing.T) {
+	convey.Convey("Collectors enabled list", t, func() {
+		convey.Convey("Valid list", func() {
+			enabled, err := parseCollectorsEnabled("global_status, slave_status,global_status")
+			convey.So(err, convey.ShouldBeNil)
+			var names []string
+			for _, scraper := range enabled {
+				names = append(names, scraper.Name())
+			}
+			convey.So(names, convey.ShouldResemble, []string{"global_status", "slave_status"})
+		})
+		convey.Convey("Unknown collector", func() {
+			_, err := parseCollectorsEnabled("global_status,slave_stauts")
+			convey.So(err, convey.ShouldBeError, `unknown collector "slave_stauts"`)
+		})
+	})
+}
+
+func TestResolveDSN(t *testing.T) {
+	convey.Convey("DSN resolution", t, func() {
+		convey.Convey("Environment wins over my.cnf", func() {
+			dsn, err := resolveDSN("user@tcp(db:3306)/", false, "/nonexistent/.my.cnf")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(dsn, convey.ShouldEqual, "user@tcp(db:3306)/")
+		})
+		convey.Convey("my.cnf is read without environment", func() {
+			_, err := resolveDSN("", false, "/nonexistent/.my.cnf")
+			convey.So(err, convey.ShouldBeError)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "failed reading ini file")
+		})
+		convey.Convey("my.cnf is skipped", func() {
+			_, err := resolveDSN("", true, "/nonexistent/.my.cnf")
+			convey.So(err, convey.ShouldBeError, "DATA_SOURCE_NAME must be set when --config.skip-my-cnf is used")
+		})
+	})
+}
+
+func TestScrapeLimiter(t *testing.T) {
+	convey.Convey("Scrape limiter", t, func() {
+		convey.Convey("No limit", func() {
+			limiter := newScrapeLimiter(0)
+			for i := 0; i < 3; i++ {
+				convey.So(limiter.acquire(context.Background()), convey.ShouldBeTrue)
+			}
+			limiter.release()
+		})
+		convey.Convey("Limit reached until the timeout", func() {
+			limiter := newScrapeLimiter(1)
+			convey.So(limiter.acquire(context.Background()), convey.ShouldBeTrue)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			convey.So(limiter.acquire(ctx), convey.ShouldBeFalse)
+
+			limiter.release()
+			convey.So(limiter.acquire(context.Background()), convey.ShouldBeTrue)
+		})
+	})
+}
+
+func TestMetricNamespace(t *testing.T) {
+	defer kingpin.CommandLine.Parse([]string{})
+
+	convey.Convey("Metric namespace", t, func() {
+		convey.Convey("Valid namespace", func() {
+			_, err := kingpin.CommandLine.Parse([]string{"--metric.namespace=mysql_fork"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(*metricNamespace, convey.ShouldEqual, "mysql_fork")
+		})
+		convey.Convey("Invalid namespace", func() {
+			_, err := kingpin.CommandLine.Parse([]string{"--metric.namespace=mysql-fork"})
+			convey.So(err, convey.ShouldBeError)
+		})
+	})
+}
+
+func TestShardLabelName(t *testing.T) {
+	defer kingpin.CommandLine.Parse([]string{})
+
+	convey.Convey("Shard label name", t, func() {
+		_, err := kingpin.CommandLine.Parse([]string{"--collect.shard-label-name=keyspace"})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(*shardLabelName, convey.ShouldEqual, "keyspace")
+
+		_, err = kingpin.CommandLine.Parse([]string{"--collect.shard-label-name=key space"})
+		convey.So(err, convey.ShouldBeError)
+	})
+	convey.Convey("No shard label without query", t, func() {
+		labeler := &shardLabeler{}
+		convey.So(labeler.get(context.Background(), nil), convey.ShouldBeNil)
+	})
+	convey.Convey("Shard label is added unless the metric has it", t, func() {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(
+			prometheus.NewGauge(prometheus.GaugeOpts{Name: "mysql_up", Help: "Up."}),
+			prometheus.NewGauge(prometheus.GaugeOpts{Name: "mysql_user_connections", Help: "Connections.", ConstLabels: prometheus.Labels{"user": "app", "host": "%"}}),
+		)
+		mfs, err := labelGatherer{Gatherer: registry, labels: prometheus.Labels{"user": "ks1"}, logger: log.NewNopLogger()}.Gather()
+		convey.So(err, convey.ShouldBeNil)
+		labels := map[string]map[string]string{}
+		for _, mf := range mfs {
+			labels[mf.GetName()] = map[string]string{}
+			var names []string
+			for _, pair := range mf.Metric[0].Label {
+				labels[mf.GetName()][pair.GetName()] = pair.GetValue()
+				names = append(names, pair.GetName())
+			}
+			convey.So(sort.StringsAreSorted(names), convey.ShouldBeTrue)
+		}
+		convey.So(labels, convey.ShouldResemble, map[string]map[string]string{
+			"mysql_up":               {"user": "ks1"},
+			"mysql_user_connections": {"host": "%", "user": "app"},
+		})
+	})
+}
+
+func TestWarmup(t *testing.T) {
+	defer func(oldDSN string) { dsn = oldDSN }(dsn)
+	// Nothing listens there, the collection fails fast but still counts.
+	dsn = "user@tcp(127.0.0.1:1)/"
+
+	convey.Convey("Warmup collection", t, func() {
+		metrics := collector.NewMetrics()
+		warmup(metrics, []collector.Scraper{collector.ScrapeGlobalStatus{}}, log.NewNopLogger())
+		convey.So(testutil.ToFloat64(metrics.TotalScrapes), convey.ShouldEqual, 1)
+		convey.So(testutil.ToFloat64(metrics.Error), convey.ShouldEqual, 1)
+	})
+}
+
+func TestRecentErrorsHandler(t *testing.T) {
+	if _, err := kingpin.CommandLine.Parse([]string{"--exporter.recent-errors=5"}); err != nil {
+		t.Fatal(err)
+	}
+	defer kingpin.CommandLine.Parse([]string{})
+	defer func(oldDSN string) { dsn = oldDSN }(dsn)
+	dsn = "user@tcp(127.0.0.1:1)/"
+
+	convey.Convey("Recent scrape errors", t, func() {
+		metrics := collector.NewMetrics()
+		convey.So(metrics.RecentErrors, convey.ShouldNotBeNil)
+		warmup(metrics, []collector.Scraper{collector.ScrapeGlobalStatus{}}, log.NewNopLogger())
+
+		rec := httptest.NewRecorder()
+		newRecentErrorsHandler(metrics.RecentErrors, log.NewNopLogger())(rec, httptest.NewRequest("GET", "/debug/errors", nil))
+		convey.So(rec.Header().Get("Content-Type"), convey.ShouldEqual, "application/json")
+
+		var recentErrors []collector.ScrapeError
+		convey.So(json.Unmarshal(rec.Body.Bytes(), &recentErrors), convey.ShouldBeNil)
+		convey.So(recentErrors, convey.ShouldHaveLength, 1)
+		convey.So(recentErrors[0].Collector, convey.ShouldEqual, "connection")
+		convey.So(recentErrors[0].Error, convey.ShouldNotBeEmpty)
+	})
+}
+
 // bin stores information about path of executable and attached port
 type bin struct {
 	path string
 	port int
 }
 
+func TestReloadDSN(t *testing.T) {
+	defer func(oldDSN string) { dsn = oldDSN }(dsn)
+	defer func(oldMycnf string) { *configMycnf = oldMycnf }(*configMycnf)
+	dsn = "exporter:old@tcp(127.0.0.1:3306)/"
+
+	mycnf, err := ioutil.TempFile("", "my.cnf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(mycnf.Name())
+	*configMycnf = mycnf.Name()
+
+	convey.Convey("Reload DSN", t, func() {
+		convey.Convey("Invalid file keeps the previous DSN", func() {
+			convey.So(ioutil.WriteFile(mycnf.Name(), []byte("[client]\npassword=rotated\n"), 0600), convey.ShouldBeNil)
+			convey.So(reloadDSN(context.Background()), convey.ShouldNotBeNil)
+			convey.So(currentDSN(), convey.ShouldEqual, "exporter:old@tcp(127.0.0.1:3306)/")
+		})
+		convey.Convey("Unreachable server keeps the previous DSN", func() {
+			// Nothing listens there.
+			convey.So(ioutil.WriteFile(mycnf.Name(), []byte("[client]\nuser=exporter\npassword=rotated\nhost=127.0.0.1\nport=1\n"), 0600), convey.ShouldBeNil)
+			convey.So(reloadDSN(context.Background()), convey.ShouldNotBeNil)
+			convey.So(currentDSN(), convey.ShouldEqual, "exporter:old@tcp(127.0.0.1:3306)/")
+		})
+	})
+}
+
 // TestBin builds, runs and tests binary.
 func TestBin(t *testing.T) {
 	var err error